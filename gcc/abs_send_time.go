@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// absSendTimeSize is the size of the abs-send-time header extension in
+	// bytes.
+	absSendTimeSize = 3
+	// absSendTimeBits is the width of the abs-send-time timestamp, which is a
+	// 6.18 fixed point number of seconds and wraps around every 64 seconds.
+	absSendTimeBits         = 24
+	absSendTimeFractionBits = 18
+)
+
+var errInvalidAbsSendTime = errors.New("invalid abs-send-time header extension")
+
+// parseAbsSendTime returns the timestamp of the abs-send-time header extension
+// raw.
+func parseAbsSendTime(raw []byte) (uint32, error) {
+	if len(raw) != absSendTimeSize {
+		return 0, fmt.Errorf("%w: size %d, expected %d", errInvalidAbsSendTime, len(raw), absSendTimeSize)
+	}
+
+	return uint32(raw[0])<<16 | uint32(raw[1])<<8 | uint32(raw[2]), nil
+}
+
+// absSendTimeUnwrapper converts abs-send-time timestamps to departure times
+// which do not wrap around. The departure times are relative to the zero time
+// and only their differences are meaningful.
+type absSendTimeUnwrapper struct {
+	unwrapper *unwrapper
+}
+
+func newAbsSendTimeUnwrapper() *absSendTimeUnwrapper {
+	return &absSendTimeUnwrapper{
+		unwrapper: newUnwrapper(absSendTimeBits),
+	}
+}
+
+// departure returns the departure time of a packet with the abs-send-time
+// timestamp.
+func (u *absSendTimeUnwrapper) departure(timestamp uint32) time.Time {
+	unwrapped := u.unwrapper.unwrap(uint64(timestamp))
+	seconds := unwrapped >> absSendTimeFractionBits
+	fraction := unwrapped & (1<<absSendTimeFractionBits - 1)
+
+	return time.Time{}.Add(
+		time.Duration(seconds)*time.Second + time.Duration(fraction)*time.Second>>absSendTimeFractionBits,
+	)
+}
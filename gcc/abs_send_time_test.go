@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAbsSendTime(t *testing.T) {
+	cases := []struct {
+		name     string
+		raw      []byte
+		expected uint32
+		err      error
+	}{
+		{name: "zero", raw: []byte{0, 0, 0}, expected: 0, err: nil},
+		{name: "oneSecond", raw: []byte{0x04, 0x00, 0x00}, expected: 1 << 18, err: nil},
+		{name: "max", raw: []byte{0xff, 0xff, 0xff}, expected: 1<<24 - 1, err: nil},
+		{name: "missing", raw: nil, expected: 0, err: errInvalidAbsSendTime},
+		{name: "tooShort", raw: []byte{1, 2}, expected: 0, err: errInvalidAbsSendTime},
+		{name: "tooLong", raw: []byte{1, 2, 3, 4}, expected: 0, err: errInvalidAbsSendTime},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			timestamp, err := parseAbsSendTime(tc.raw)
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.expected, timestamp)
+		})
+	}
+}
+
+func TestAbsSendTimeUnwrapper(t *testing.T) {
+	cases := []struct {
+		name       string
+		timestamps []uint32
+		expected   []time.Duration
+	}{
+		{
+			name:       "inOrder",
+			timestamps: []uint32{1 << 18, 1<<18 + 1<<17, 2 << 18},
+			expected:   []time.Duration{time.Second, 1500 * time.Millisecond, 2 * time.Second},
+		},
+		{
+			name:       "fraction",
+			timestamps: []uint32{1},
+			expected:   []time.Duration{3814},
+		},
+		{
+			name:       "wrapAround",
+			timestamps: []uint32{63 << 18, 1<<24 - 1<<17, 1 << 17, 1 << 18},
+			expected: []time.Duration{
+				63 * time.Second, 63500 * time.Millisecond, 64500 * time.Millisecond, 65 * time.Second,
+			},
+		},
+		{
+			name:       "reorderedAcrossWrapAround",
+			timestamps: []uint32{1 << 17, 1<<24 - 1<<17, 1 << 18},
+			expected:   []time.Duration{500 * time.Millisecond, -500 * time.Millisecond, time.Second},
+		},
+		{
+			name:       "secondWrapAround",
+			timestamps: []uint32{0, 30 << 18, 60 << 18, 26 << 18, 56 << 18, 22 << 18},
+			expected: []time.Duration{
+				0, 30 * time.Second, 60 * time.Second, 90 * time.Second, 120 * time.Second, 150 * time.Second,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := newAbsSendTimeUnwrapper()
+			for i, timestamp := range tc.timestamps {
+				assert.Equal(t, time.Time{}.Add(tc.expected[i]), u.departure(timestamp))
+			}
+		})
+	}
+}
+
+func TestAbsSendTimeUnwrapperFeedsArrivalGroupAccumulator(t *testing.T) {
+	// Packets sent 2ms apart across the wrap-around form groups of burst
+	// interval like any other packets.
+	u := newAbsSendTimeUnwrapper()
+	a, err := newArrivalGroupAccumulator()
+	assert.NoError(t, err)
+	start := uint32(1<<24 - 5<<18/1000)
+	arrival := time.Time{}
+	var groups []arrivalGroup
+	for i := 0; i < 8; i++ {
+		timestamp := (start + uint32(i*2<<18/1000)) % (1 << 24) // nolint:gosec
+		arrival = arrival.Add(2 * time.Millisecond)
+		if group := a.onPacketAcked(uint64(i), 1200, u.departure(timestamp), arrival); group != nil { // nolint:gosec
+			groups = append(groups, group)
+		}
+	}
+	assert.Len(t, groups, 2)
+	assert.Len(t, groups[0], 3)
+	assert.Len(t, groups[1], 3)
+}
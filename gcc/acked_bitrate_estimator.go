@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// initialRateWindow is the window of the first sample of the Bayesian
+	// estimator and rateWindow the window of all further samples.
+	initialRateWindow = 500 * time.Millisecond
+	rateWindow        = 150 * time.Millisecond
+
+	// rateUncertaintyScale scales the difference between a sample and the
+	// estimate to the uncertainty of the sample. Samples taken while the
+	// application is limited or from small windows which are below the
+	// estimate are scaled more, because they don't show the capacity of the
+	// path.
+	rateUncertaintyScale            = 10.0
+	rateUncertaintyScaleInALR       = 20.0
+	rateUncertaintyScaleSmallSample = 20.0
+
+	// smallSampleThreshold is the number of bytes below which a window is a
+	// small sample.
+	smallSampleThreshold = 4 * assumedPacketSize
+
+	// rateProcessNoise is the variance in kbps^2 added to the estimate before
+	// each sample, which keeps the estimate adaptive.
+	rateProcessNoise = 5.0
+	// fastRateChangeVariance is the variance in kbps^2 added to the estimate
+	// when the rate is expected to change quickly, e.g. when the application
+	// limited region ended.
+	fastRateChangeVariance = 200.0
+)
+
+// ackedBitrateEstimator estimates the rate at which the remote peer receives
+// packets from the acknowledged packets.
+type ackedBitrateEstimator interface {
+	// onPacketAcked adds a packet of size bytes which arrived at arrival.
+	// inALR reports whether the application is limited.
+	onPacketAcked(arrival time.Time, size int, inALR bool)
+	// estimate returns the estimate in bits per second, 0 if there is no
+	// estimate, and the confidence in the estimate between 0 and 1.
+	estimate() (int, float64)
+}
+
+// windowedAckedBitrateEstimator measures the received bitrate over a sliding
+// window. It reports every measurement with full confidence.
+type windowedAckedBitrateEstimator struct {
+	deliveryRateEstimator *deliveryRateEstimator
+}
+
+func newWindowedAckedBitrateEstimator(window time.Duration) *windowedAckedBitrateEstimator {
+	return &windowedAckedBitrateEstimator{
+		deliveryRateEstimator: newDeliveryRateEstimator(window),
+	}
+}
+
+func (e *windowedAckedBitrateEstimator) onPacketAcked(arrival time.Time, size int, _ bool) {
+	e.deliveryRateEstimator.onPacketAcked(arrival, size)
+}
+
+func (e *windowedAckedBitrateEstimator) estimate() (int, float64) {
+	return e.deliveryRateEstimator.getRate(), 1
+}
+
+// bayesianAckedBitrateEstimator estimates the received bitrate like the
+// BitrateEstimator of libwebrtc. It takes a sample of the bitrate per window
+// of arrival time and combines each sample with the previous estimate,
+// weighted by their variances. The variance of a sample grows with its
+// distance from the estimate, so single outliers have little effect, while a
+// persistent change is followed within a few windows. The estimate is kept
+// when no packets are acknowledged.
+type bayesianAckedBitrateEstimator struct {
+	// estimateKbps is the estimate in kbps and varianceKbps2 its variance
+	// in kbps^2. A negative estimate means no estimate is available.
+	estimateKbps  float64
+	varianceKbps2 float64
+
+	lastArrival   time.Time
+	currentWindow time.Duration
+	windowBytes   int
+
+	inALR bool
+}
+
+func newBayesianAckedBitrateEstimator() *bayesianAckedBitrateEstimator {
+	return &bayesianAckedBitrateEstimator{
+		estimateKbps:  -1,
+		varianceKbps2: 50,
+		lastArrival:   time.Time{},
+		currentWindow: 0,
+		windowBytes:   0,
+		inALR:         false,
+	}
+}
+
+func (e *bayesianAckedBitrateEstimator) onPacketAcked(arrival time.Time, size int, inALR bool) {
+	if e.inALR && !inALR {
+		// The application uses the full bitrate again, which may reveal a
+		// much higher rate than measured during the application limited
+		// region.
+		e.varianceKbps2 += fastRateChangeVariance
+	}
+	e.inALR = inALR
+
+	window := rateWindow
+	if e.estimateKbps < 0 {
+		window = initialRateWindow
+	}
+	sampleKbps, smallSample, ok := e.updateWindow(arrival, size, window)
+	if !ok {
+		return
+	}
+	if e.estimateKbps < 0 {
+		e.estimateKbps = sampleKbps
+
+		return
+	}
+	scale := rateUncertaintyScale
+	if sampleKbps < e.estimateKbps {
+		if inALR {
+			scale = rateUncertaintyScaleInALR
+		} else if smallSample {
+			scale = rateUncertaintyScaleSmallSample
+		}
+	}
+	sampleUncertainty := scale * math.Abs(e.estimateKbps-sampleKbps) / e.estimateKbps
+	sampleVariance := sampleUncertainty * sampleUncertainty
+	predictedVariance := e.varianceKbps2 + rateProcessNoise
+	e.estimateKbps = (sampleVariance*e.estimateKbps + predictedVariance*sampleKbps) /
+		(sampleVariance + predictedVariance)
+	e.varianceKbps2 = sampleVariance * predictedVariance / (sampleVariance + predictedVariance)
+}
+
+// updateWindow adds a packet to the current window and returns a sample in
+// kbps if the window is complete.
+func (e *bayesianAckedBitrateEstimator) updateWindow(
+	arrival time.Time,
+	size int,
+	window time.Duration,
+) (float64, bool, bool) {
+	if arrival.Before(e.lastArrival) {
+		// Reordered or a jump of the remote clock, start over.
+		e.lastArrival = time.Time{}
+		e.currentWindow = 0
+		e.windowBytes = 0
+	}
+	if !e.lastArrival.IsZero() {
+		elapsed := arrival.Sub(e.lastArrival)
+		e.currentWindow += elapsed
+		if elapsed > window {
+			// Nothing arrived for a whole window, which says nothing about the
+			// rate the path supports.
+			e.windowBytes = 0
+			e.currentWindow %= window
+		}
+	}
+	e.lastArrival = arrival
+
+	sampleKbps := 0.0
+	smallSample := false
+	ok := false
+	if e.currentWindow >= window {
+		smallSample = e.windowBytes < smallSampleThreshold
+		sampleKbps = 8 * float64(e.windowBytes) / float64(window.Milliseconds())
+		e.currentWindow -= window
+		e.windowBytes = 0
+		ok = true
+	}
+	e.windowBytes += size
+
+	return sampleKbps, smallSample, ok
+}
+
+// estimate returns the estimate and a confidence of one minus the standard
+// deviation relative to the estimate.
+func (e *bayesianAckedBitrateEstimator) estimate() (int, float64) {
+	if e.estimateKbps <= 0 {
+		return 0, 0
+	}
+	confidence := 1 - math.Sqrt(e.varianceKbps2)/e.estimateKbps
+
+	return int(1000 * e.estimateKbps), max(0, min(confidence, 1))
+}
@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ackAtRate acknowledges a packet every 10ms for duration at bitrate and
+// returns the time after the last packet.
+func ackAtRate(e ackedBitrateEstimator, start time.Time, duration time.Duration, bitrate int, inALR bool) time.Time {
+	interval := 10 * time.Millisecond
+	size := int(float64(bitrate) * interval.Seconds() / 8)
+	now := start
+	for ; now.Before(start.Add(duration)); now = now.Add(interval) {
+		e.onPacketAcked(now, size, inALR)
+	}
+
+	return now
+}
+
+func TestWindowedAckedBitrateEstimator(t *testing.T) {
+	e := newWindowedAckedBitrateEstimator(time.Second)
+	bitrate, confidence := e.estimate()
+	assert.Equal(t, 0, bitrate)
+	assert.Equal(t, 1.0, confidence)
+
+	ackAtRate(e, time.Time{}.Add(time.Second), 2*time.Second, 1_000_000, false)
+	bitrate, confidence = e.estimate()
+	assert.InDelta(t, 1_000_000, bitrate, 10_000)
+	assert.Equal(t, 1.0, confidence)
+}
+
+func TestBayesianAckedBitrateEstimator(t *testing.T) {
+	cases := []struct {
+		name string
+		// phases are acknowledged one after the other, each for a second.
+		phases     []int
+		inALR      []bool
+		expected   int
+		delta      float64
+		confidence float64
+	}{
+		{
+			name:       "constantRate",
+			phases:     []int{1_000_000, 1_000_000},
+			inALR:      []bool{false, false},
+			expected:   1_000_000,
+			delta:      10_000,
+			confidence: 0.99,
+		},
+		{
+			name:       "increase",
+			phases:     []int{1_000_000, 2_000_000, 2_000_000},
+			inALR:      []bool{false, false, false},
+			expected:   2_000_000,
+			delta:      100_000,
+			confidence: 0.99,
+		},
+		{
+			name:       "decrease",
+			phases:     []int{2_000_000, 500_000, 500_000},
+			inALR:      []bool{false, false, false},
+			expected:   500_000,
+			delta:      50_000,
+			confidence: 0.9,
+		},
+		{
+			// Samples below the estimate taken while application limited
+			// barely move it.
+			name:       "applicationLimited",
+			phases:     []int{2_000_000, 500_000},
+			inALR:      []bool{false, true},
+			expected:   1_450_000,
+			delta:      100_000,
+			confidence: 0.99,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newBayesianAckedBitrateEstimator()
+			now := time.Time{}.Add(time.Second)
+			for i, bitrate := range tc.phases {
+				now = ackAtRate(e, now, time.Second, bitrate, tc.inALR[i])
+			}
+			bitrate, confidence := e.estimate()
+			assert.InDelta(t, tc.expected, bitrate, tc.delta)
+			assert.GreaterOrEqual(t, confidence, tc.confidence)
+			assert.LessOrEqual(t, confidence, 1.0)
+		})
+	}
+}
+
+func TestBayesianAckedBitrateEstimatorIgnoresApplicationLimitedRegion(t *testing.T) {
+	limited := newBayesianAckedBitrateEstimator()
+	unlimited := newBayesianAckedBitrateEstimator()
+	start := time.Time{}.Add(time.Second)
+	ackAtRate(limited, start, time.Second, 2_000_000, false)
+	now := ackAtRate(unlimited, start, time.Second, 2_000_000, false)
+	ackAtRate(limited, now, 500*time.Millisecond, 500_000, true)
+	ackAtRate(unlimited, now, 500*time.Millisecond, 500_000, false)
+
+	limitedBitrate, _ := limited.estimate()
+	unlimitedBitrate, _ := unlimited.estimate()
+	assert.Greater(t, limitedBitrate, unlimitedBitrate)
+}
+
+func TestBayesianAckedBitrateEstimatorInitialWindow(t *testing.T) {
+	e := newBayesianAckedBitrateEstimator()
+	now := ackAtRate(e, time.Time{}.Add(time.Second), 400*time.Millisecond, 1_000_000, false)
+	bitrate, confidence := e.estimate()
+	assert.Equal(t, 0, bitrate)
+	assert.Equal(t, 0.0, confidence)
+
+	now = ackAtRate(e, now, 200*time.Millisecond, 1_000_000, false)
+	bitrate, _ = e.estimate()
+	assert.InDelta(t, 1_000_000, bitrate, 10_000)
+
+	// Unlike the windowed estimator, the estimate is kept when nothing is
+	// acknowledged for a while.
+	e.onPacketAcked(now.Add(5*time.Second), 1250, false)
+	bitrate, _ = e.estimate()
+	assert.InDelta(t, 1_000_000, bitrate, 10_000)
+}
+
+func TestBayesianAckedBitrateEstimatorSmallSamples(t *testing.T) {
+	// A single window with few bytes is a weaker signal than a window with
+	// many packets which all arrived at a lower rate.
+	small := newBayesianAckedBitrateEstimator()
+	large := newBayesianAckedBitrateEstimator()
+	start := time.Time{}.Add(time.Second)
+	now := ackAtRate(small, start, time.Second, 1_000_000, false)
+	ackAtRate(large, start, time.Second, 1_000_000, false)
+	// 160 kbps in a 150ms window is 3000 bytes, below the small sample
+	// threshold, 400 kbps is 7500 bytes.
+	ackAtRate(small, now, 150*time.Millisecond, 160_000, false)
+	ackAtRate(large, now, 150*time.Millisecond, 400_000, false)
+	smallBitrate, _ := small.estimate()
+	largeBitrate, _ := large.estimate()
+	assert.Greater(t, smallBitrate, largeBitrate)
+}
@@ -0,0 +1,92 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+)
+
+const (
+	// alrBandwidthUsageRatio is the fraction of the target bitrate the
+	// application must use to not be application limited.
+	alrBandwidthUsageRatio = 0.65
+	// alrStartBudgetRatio and alrStopBudgetRatio are the fractions of the
+	// budget that must be left unused to enter and may be left unused to
+	// leave the application limited region.
+	alrStartBudgetRatio = 0.80
+	alrStopBudgetRatio  = 0.50
+	// alrBudgetWindow is the time over which unused budget is accumulated.
+	alrBudgetWindow = 500 * time.Millisecond
+)
+
+type alrEvent int
+
+const (
+	alrEventNone alrEvent = iota
+	alrEventStart
+	alrEventEnd
+)
+
+// alrDetector detects whether the application sends less than the target
+// bitrate allows, e.g. because the encoder produces less data than requested
+// or the content is static. It keeps a budget which grows at a fraction of
+// the target bitrate and shrinks by the bytes sent, and enters the application
+// limited region (ALR) when most of the budget is left unused over a window of
+// alrBudgetWindow. This follows the AlrDetector of libwebrtc.
+type alrDetector struct {
+	targetBitrate int
+	budget        float64
+	lastSend      time.Time
+	inALR         bool
+}
+
+func newALRDetector(targetBitrate int) *alrDetector {
+	return &alrDetector{
+		targetBitrate: targetBitrate,
+		budget:        0,
+		lastSend:      time.Time{},
+		inALR:         false,
+	}
+}
+
+func (d *alrDetector) setTargetBitrate(bitrate int) {
+	d.targetBitrate = bitrate
+}
+
+// maxBudget returns the largest budget in bytes, which is also the largest
+// debt.
+func (d *alrDetector) maxBudget() float64 {
+	return alrBandwidthUsageRatio * float64(d.targetBitrate) * alrBudgetWindow.Seconds() / 8
+}
+
+// onPacketSent adds a packet of size bytes sent at departure and returns
+// whether the application limited region started or ended.
+func (d *alrDetector) onPacketSent(size int, departure time.Time) alrEvent {
+	if d.lastSend.IsZero() {
+		d.lastSend = departure
+	}
+	elapsed := max(departure.Sub(d.lastSend), 0)
+	d.lastSend = departure
+
+	maxBudget := d.maxBudget()
+	if maxBudget <= 0 {
+		return alrEventNone
+	}
+	d.budget = max(d.budget-float64(size), -maxBudget)
+	d.budget = min(d.budget+alrBandwidthUsageRatio*float64(d.targetBitrate)*elapsed.Seconds()/8, maxBudget)
+
+	ratio := d.budget / maxBudget
+	switch {
+	case !d.inALR && ratio > alrStartBudgetRatio:
+		d.inALR = true
+
+		return alrEventStart
+	case d.inALR && ratio < alrStopBudgetRatio:
+		d.inALR = false
+
+		return alrEventEnd
+	default:
+		return alrEventNone
+	}
+}
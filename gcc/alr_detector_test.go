@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sendAtRate sends a packet every 10ms for duration at usage times the target
+// bitrate of d and returns the events and the time after the last packet.
+func sendAtRate(d *alrDetector, start time.Time, duration time.Duration, usage float64) ([]alrEvent, time.Time) {
+	events := []alrEvent{}
+	interval := 10 * time.Millisecond
+	size := int(usage * float64(d.targetBitrate) * interval.Seconds() / 8)
+	now := start
+	for ; now.Before(start.Add(duration)); now = now.Add(interval) {
+		if event := d.onPacketSent(size, now); event != alrEventNone {
+			events = append(events, event)
+		}
+	}
+
+	return events, now
+}
+
+func TestALRDetector(t *testing.T) {
+	cases := []struct {
+		name     string
+		usage    []float64
+		expected []alrEvent
+		inALR    bool
+	}{
+		{name: "fullUsage", usage: []float64{1}, expected: []alrEvent{}, inALR: false},
+		{name: "aboveUsageRatio", usage: []float64{0.7}, expected: []alrEvent{}, inALR: false},
+		{name: "lowUsage", usage: []float64{0.1}, expected: []alrEvent{alrEventStart}, inALR: true},
+		{name: "idle", usage: []float64{0}, expected: []alrEvent{alrEventStart}, inALR: true},
+		{
+			name:     "lowThenFullUsage",
+			usage:    []float64{0.1, 1},
+			expected: []alrEvent{alrEventStart, alrEventEnd},
+			inALR:    false,
+		},
+		{
+			name:     "lowFullLowUsage",
+			usage:    []float64{0.1, 1, 0.2},
+			expected: []alrEvent{alrEventStart, alrEventEnd, alrEventStart},
+			inALR:    true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := newALRDetector(1_000_000)
+			events := []alrEvent{}
+			now := time.Time{}.Add(time.Second)
+			for _, usage := range tc.usage {
+				var e []alrEvent
+				e, now = sendAtRate(d, now, 2*time.Second, usage)
+				events = append(events, e...)
+			}
+			assert.Equal(t, tc.expected, events)
+			assert.Equal(t, tc.inALR, d.inALR)
+		})
+	}
+}
+
+func TestALRDetectorFollowsTargetBitrate(t *testing.T) {
+	d := newALRDetector(1_000_000)
+	events, now := sendAtRate(d, time.Time{}.Add(time.Second), 2*time.Second, 1)
+	assert.Empty(t, events)
+
+	// The same absolute rate is well below a higher target bitrate.
+	d.setTargetBitrate(10_000_000)
+	size := 1_000_000 * 10 / 1000 / 8
+	for range 200 {
+		now = now.Add(10 * time.Millisecond)
+		if event := d.onPacketSent(size, now); event != alrEventNone {
+			events = append(events, event)
+		}
+	}
+	assert.Equal(t, []alrEvent{alrEventStart}, events)
+}
@@ -4,9 +4,71 @@
 package gcc
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
+var errInvalidArrivalGroupOption = errors.New("invalid arrival group option")
+
+// BurstGroupingRule selects when a packet sent after the burst interval is
+// still added to the current group because it arrived in a burst with it.
+type BurstGroupingRule int
+
+const (
+	// BurstGroupingDraft adds a packet to the current group if it arrived
+	// less than the max burst duration after the first packet of the group
+	// and its delay is smaller than the delay of the first packet, as
+	// described in section 5.2 of draft-ietf-rmcat-gcc-02.
+	BurstGroupingDraft BurstGroupingRule = iota
+	// BurstGroupingArrival adds a packet to the current group if it arrived
+	// at most the max burst duration after the last packet of the group and
+	// its delay is smaller than the delay of the last packet, like the
+	// InterArrival of libwebrtc. A burst thus continues as long as packets
+	// keep arriving close to each other, up to the max group span if one is set.
+	BurstGroupingArrival
+)
+
+// ArrivalGroupOption configures how acknowledged packets are grouped before
+// the delay between the groups is estimated.
+type ArrivalGroupOption func(*arrivalGroupAccumulator)
+
+// ArrivalGroupBurstInterval sets the interval in which packets must be sent to
+// belong to the same group. The default is 5ms.
+func ArrivalGroupBurstInterval(interval time.Duration) ArrivalGroupOption {
+	return func(a *arrivalGroupAccumulator) {
+		a.burstInterval = interval
+	}
+}
+
+// ArrivalGroupMaxBurstDuration sets the interval in which packets sent later
+// than the burst interval must arrive to still belong to the same group. The
+// default is 5ms.
+func ArrivalGroupMaxBurstDuration(duration time.Duration) ArrivalGroupOption {
+	return func(a *arrivalGroupAccumulator) {
+		a.maxBurstDuration = duration
+	}
+}
+
+// ArrivalGroupMaxSpan sets the longest time between the arrivals of the first
+// and the last packet of a group. Packets arriving later start a new group,
+// even if they were sent in the burst interval, which bounds the size of
+// groups on high bitrate links. The default is 0, which does not limit the
+// span.
+func ArrivalGroupMaxSpan(span time.Duration) ArrivalGroupOption {
+	return func(a *arrivalGroupAccumulator) {
+		a.maxSpan = span
+	}
+}
+
+// ArrivalGroupBurstRule sets the rule by which packets arriving in a burst are
+// grouped. The default is BurstGroupingDraft.
+func ArrivalGroupBurstRule(rule BurstGroupingRule) ArrivalGroupOption {
+	return func(a *arrivalGroupAccumulator) {
+		a.burstRule = rule
+	}
+}
+
 type arrivalGroupItem struct {
 	SequenceNumber uint64
 	Departure      time.Time
@@ -20,14 +82,41 @@ type arrivalGroupAccumulator struct {
 	next             arrivalGroup
 	burstInterval    time.Duration
 	maxBurstDuration time.Duration
+	maxSpan          time.Duration
+	burstRule        BurstGroupingRule
 }
 
-func newArrivalGroupAccumulator() *arrivalGroupAccumulator {
-	return &arrivalGroupAccumulator{
+func newArrivalGroupAccumulator(options ...ArrivalGroupOption) (*arrivalGroupAccumulator, error) {
+	a := &arrivalGroupAccumulator{
 		next:             make([]arrivalGroupItem, 0),
 		burstInterval:    5 * time.Millisecond,
 		maxBurstDuration: 5 * time.Millisecond,
+		maxSpan:          0,
+		burstRule:        BurstGroupingDraft,
+	}
+	for _, opt := range options {
+		opt(a)
 	}
+	if a.burstInterval < 0 {
+		return nil, fmt.Errorf(
+			"%w: burst interval %v must not be negative",
+			errInvalidArrivalGroupOption, a.burstInterval,
+		)
+	}
+	if a.maxBurstDuration < 0 {
+		return nil, fmt.Errorf(
+			"%w: max burst duration %v must not be negative",
+			errInvalidArrivalGroupOption, a.maxBurstDuration,
+		)
+	}
+	if a.maxSpan < 0 {
+		return nil, fmt.Errorf("%w: max span %v must not be negative", errInvalidArrivalGroupOption, a.maxSpan)
+	}
+	if a.burstRule != BurstGroupingDraft && a.burstRule != BurstGroupingArrival {
+		return nil, fmt.Errorf("%w: unknown burst rule %v", errInvalidArrivalGroupOption, a.burstRule)
+	}
+
+	return a, nil
 }
 
 func (a *arrivalGroupAccumulator) onPacketAcked(
@@ -35,50 +124,55 @@ func (a *arrivalGroupAccumulator) onPacketAcked(
 	size int,
 	departure, arrival time.Time,
 ) arrivalGroup {
-	if len(a.next) == 0 {
-		a.next = append(a.next, arrivalGroupItem{
-			SequenceNumber: sequenceNumber,
-			Size:           size,
-			Departure:      departure,
-			Arrival:        arrival,
-		})
+	item := arrivalGroupItem{
+		SequenceNumber: sequenceNumber,
+		Size:           size,
+		Departure:      departure,
+		Arrival:        arrival,
+	}
+	if len(a.next) == 0 || a.belongsToGroup(item) {
+		a.next = append(a.next, item)
 
 		return nil
 	}
 
-	sendTimeDelta := departure.Sub(a.next[0].Departure)
-	if sendTimeDelta < a.burstInterval {
-		a.next = append(a.next, arrivalGroupItem{
-			SequenceNumber: sequenceNumber,
-			Size:           size,
-			Departure:      departure,
-			Arrival:        arrival,
-		})
+	group := a.next
+	a.next = arrivalGroup{item}
 
-		return nil
+	return group
+}
+
+// belongsToGroup returns whether item belongs to the current group, which
+// must not be empty.
+func (a *arrivalGroupAccumulator) belongsToGroup(item arrivalGroupItem) bool {
+	first := a.next[0]
+	if a.maxSpan > 0 && item.Arrival.Sub(first.Arrival) >= a.maxSpan {
+		return false
+	}
+	if a.burstRule == BurstGroupingArrival {
+		return a.belongsToArrivalBurst(item) || item.Departure.Sub(first.Departure) < a.burstInterval
 	}
 
-	arrivalTimeDeltaFirst := arrival.Sub(a.next[0].Arrival)
+	sendTimeDelta := item.Departure.Sub(first.Departure)
+	if sendTimeDelta < a.burstInterval {
+		return true
+	}
+	arrivalTimeDeltaFirst := item.Arrival.Sub(first.Arrival)
 	propagationDelta := arrivalTimeDeltaFirst - sendTimeDelta
 
-	if propagationDelta < 0 && arrivalTimeDeltaFirst < a.maxBurstDuration {
-		a.next = append(a.next, arrivalGroupItem{
-			SequenceNumber: sequenceNumber,
-			Size:           size,
-			Departure:      departure,
-			Arrival:        arrival,
-		})
+	return propagationDelta < 0 && arrivalTimeDeltaFirst < a.maxBurstDuration
+}
 
-		return nil
+// belongsToArrivalBurst returns whether item arrived in a burst with the last
+// packet of the current group.
+func (a *arrivalGroupAccumulator) belongsToArrivalBurst(item arrivalGroupItem) bool {
+	last := a.next[len(a.next)-1]
+	sendTimeDelta := item.Departure.Sub(last.Departure)
+	if sendTimeDelta == 0 {
+		return true
 	}
+	arrivalTimeDelta := item.Arrival.Sub(last.Arrival)
+	propagationDelta := arrivalTimeDelta - sendTimeDelta
 
-	group := a.next
-	a.next = arrivalGroup{arrivalGroupItem{
-		SequenceNumber: sequenceNumber,
-		Size:           size,
-		Departure:      departure,
-		Arrival:        arrival,
-	}}
-
-	return group
+	return propagationDelta < 0 && arrivalTimeDelta <= a.maxBurstDuration
 }
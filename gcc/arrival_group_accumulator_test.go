@@ -230,7 +230,8 @@ func TestArrivalGroupAccumulator(t *testing.T) {
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			aga := newArrivalGroupAccumulator()
+			aga, err := newArrivalGroupAccumulator()
+			assert.NoError(t, err)
 			received := []arrivalGroup{}
 			for _, ack := range tc.log {
 				next := aga.onPacketAcked(ack.SequenceNumber, 0, ack.Departure, ack.Arrival)
@@ -242,3 +243,165 @@ func TestArrivalGroupAccumulator(t *testing.T) {
 		})
 	}
 }
+
+func TestArrivalGroupAccumulatorOptions(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	// ms returns start plus n milliseconds.
+	ms := func(n int) time.Time {
+		return start.Add(time.Duration(n) * time.Millisecond)
+	}
+	type logItem struct {
+		departure time.Time
+		arrival   time.Time
+	}
+	cases := []struct {
+		name    string
+		options []ArrivalGroupOption
+		log     []logItem
+		// exp are the sizes of the completed groups.
+		exp []int
+	}{
+		{
+			name:    "longerBurstInterval",
+			options: []ArrivalGroupOption{ArrivalGroupBurstInterval(10 * time.Millisecond)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(4), arrival: ms(24)},
+				{departure: ms(8), arrival: ms(28)},
+				{departure: ms(12), arrival: ms(32)},
+			},
+			exp: []int{3},
+		},
+		{
+			name:    "longerMaxBurstDuration",
+			options: []ArrivalGroupOption{ArrivalGroupMaxBurstDuration(20 * time.Millisecond)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(10), arrival: ms(25)},
+				{departure: ms(20), arrival: ms(30)},
+				{departure: ms(30), arrival: ms(45)},
+			},
+			exp: []int{3},
+		},
+		{
+			name: "noMaxSpanByDefault",
+			options: []ArrivalGroupOption{
+				ArrivalGroupBurstInterval(200 * time.Millisecond),
+			},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(50), arrival: ms(70)},
+				{departure: ms(100), arrival: ms(120)},
+				{departure: ms(150), arrival: ms(170)},
+				{departure: ms(300), arrival: ms(320)},
+			},
+			exp: []int{4},
+		},
+		{
+			name: "maxSpanSplitsGroup",
+			options: []ArrivalGroupOption{
+				ArrivalGroupBurstInterval(100 * time.Millisecond),
+				ArrivalGroupMaxSpan(5 * time.Millisecond),
+			},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(1), arrival: ms(22)},
+				{departure: ms(2), arrival: ms(24)},
+				{departure: ms(3), arrival: ms(26)},
+				{departure: ms(4), arrival: ms(28)},
+			},
+			exp: []int{3},
+		},
+		{
+			name:    "draftBurstEndsAfterMaxBurstDuration",
+			options: []ArrivalGroupOption{ArrivalGroupBurstRule(BurstGroupingDraft)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(10), arrival: ms(22)},
+				{departure: ms(20), arrival: ms(24)},
+				{departure: ms(30), arrival: ms(26)},
+			},
+			exp: []int{3},
+		},
+		{
+			name:    "arrivalBurstContinues",
+			options: []ArrivalGroupOption{ArrivalGroupBurstRule(BurstGroupingArrival)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(10), arrival: ms(22)},
+				{departure: ms(20), arrival: ms(24)},
+				{departure: ms(30), arrival: ms(26)},
+				{departure: ms(40), arrival: ms(50)},
+			},
+			exp: []int{4},
+		},
+		{
+			// A packet sent exactly the burst interval after the first one
+			// starts a new group, whatever the burst rule.
+			name:    "draftBurstIntervalBoundary",
+			options: []ArrivalGroupOption{ArrivalGroupBurstRule(BurstGroupingDraft)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(5), arrival: ms(25)},
+				{departure: ms(10), arrival: ms(30)},
+			},
+			exp: []int{1, 1},
+		},
+		{
+			name:    "arrivalBurstIntervalBoundary",
+			options: []ArrivalGroupOption{ArrivalGroupBurstRule(BurstGroupingArrival)},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(5), arrival: ms(25)},
+				{departure: ms(10), arrival: ms(30)},
+			},
+			exp: []int{1, 1},
+		},
+		{
+			name: "arrivalBurstEndsAfterMaxSpan",
+			options: []ArrivalGroupOption{
+				ArrivalGroupBurstRule(BurstGroupingArrival),
+				ArrivalGroupMaxSpan(5 * time.Millisecond),
+			},
+			log: []logItem{
+				{departure: ms(0), arrival: ms(20)},
+				{departure: ms(10), arrival: ms(22)},
+				{departure: ms(20), arrival: ms(24)},
+				{departure: ms(30), arrival: ms(26)},
+			},
+			exp: []int{3},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aga, err := newArrivalGroupAccumulator(tc.options...)
+			assert.NoError(t, err)
+			received := []int{}
+			for i, item := range tc.log {
+				if group := aga.onPacketAcked(uint64(i), 0, item.departure, item.arrival); group != nil { // nolint:gosec
+					received = append(received, len(group))
+				}
+			}
+			assert.Equal(t, tc.exp, received)
+		})
+	}
+}
+
+func TestArrivalGroupAccumulatorInvalidOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options []ArrivalGroupOption
+	}{
+		{name: "negativeBurstInterval", options: []ArrivalGroupOption{ArrivalGroupBurstInterval(-1)}},
+		{name: "negativeMaxBurstDuration", options: []ArrivalGroupOption{ArrivalGroupMaxBurstDuration(-1)}},
+		{name: "negativeMaxSpan", options: []ArrivalGroupOption{ArrivalGroupMaxSpan(-1)}},
+		{name: "unknownBurstRule", options: []ArrivalGroupOption{ArrivalGroupBurstRule(7)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			aga, err := newArrivalGroupAccumulator(tc.options...)
+			assert.ErrorIs(t, err, errInvalidArrivalGroupOption)
+			assert.Nil(t, aga)
+		})
+	}
+}
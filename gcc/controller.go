@@ -0,0 +1,517 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	defaultInitialBitrate = 300_000
+	defaultMinBitrate     = 10_000
+	defaultMaxBitrate     = 50_000_000
+
+	deliveryRateWindow = time.Second
+
+	// pacerQueueDrainTime is the time in which the target bitrate leaves room
+	// to drain the pacer queue.
+	pacerQueueDrainTime = time.Second
+
+	// maxProbeClusters is the number of probe clusters remembered to match
+	// acknowledged probe packets to their cluster.
+	maxProbeClusters = 16
+)
+
+var errInvalidBitrate = errors.New("invalid bitrate")
+
+// Option configures a Controller.
+type Option func(*Controller) error
+
+// WithInitialBitrate sets the bitrate in bits per second the Controller
+// starts with.
+func WithInitialBitrate(rate int) Option {
+	return func(c *Controller) error {
+		c.initialBitrate = rate
+
+		return nil
+	}
+}
+
+// WithMinBitrate sets the lowest bitrate in bits per second the Controller
+// will ever return.
+func WithMinBitrate(rate int) Option {
+	return func(c *Controller) error {
+		c.minBitrate = rate
+
+		return nil
+	}
+}
+
+// WithMaxBitrate sets the highest bitrate in bits per second the Controller
+// will ever return.
+func WithMaxBitrate(rate int) Option {
+	return func(c *Controller) error {
+		c.maxBitrate = rate
+
+		return nil
+	}
+}
+
+// WithProbing enables or disables probing. Probing is enabled by default, but
+// the probe clusters returned by ProbeClusters are only sent if a pacer is
+// used.
+func WithProbing(enabled bool) Option {
+	return func(c *Controller) error {
+		c.probing = enabled
+
+		return nil
+	}
+}
+
+// WithALRProbing enables or disables probing while the application is limited,
+// i.e. sends less than the target bitrate allows. While application limited,
+// the delivery rate does not show whether the path supports the target
+// bitrate, so it is probed periodically instead. ALR probing is enabled by
+// default.
+func WithALRProbing(enabled bool) Option {
+	return func(c *Controller) error {
+		c.alrProbing = enabled
+
+		return nil
+	}
+}
+
+// WithALRIncreaseSuppression enables or disables suppressing increases of the
+// delay-based estimate while the application is limited. Without suppression,
+// the estimate may grow far beyond what was ever sent. Suppression is disabled
+// by default.
+func WithALRIncreaseSuppression(enabled bool) Option {
+	return func(c *Controller) error {
+		c.alrIncreaseSuppression = enabled
+
+		return nil
+	}
+}
+
+// WithBayesianAckedBitrateEstimator selects how the rate at which the remote
+// peer receives packets is measured. By default, it is measured over a sliding
+// window of one second. The Bayesian estimator instead combines samples taken
+// over short windows with the previous estimate, weighted by their uncertainty,
+// which smooths out the noise of single windows and the application limited
+// periods. Its confidence determines how far the estimate is decreased on
+// overuse.
+func WithBayesianAckedBitrateEstimator(enabled bool) Option {
+	return func(c *Controller) error {
+		c.bayesianAckedBitrate = enabled
+
+		return nil
+	}
+}
+
+// WithLossBasedBWEV2 selects how the loss-based estimate is computed. By
+// default, the estimate is increased while less than 2% of the packets are
+// lost and decreased in proportion to the loss rate while more than 10% are
+// lost. The second version instead fits an inherent loss rate and a
+// loss-limited bandwidth to the loss rates observed at different sending
+// rates, so random loss, e.g. on wireless links, does not lower the estimate
+// while loss caused by sending too much does.
+func WithLossBasedBWEV2(enabled bool) Option {
+	return func(c *Controller) error {
+		c.lossBasedBWEV2 = enabled
+
+		return nil
+	}
+}
+
+// WithTrendlineEstimator estimates the variation of the one way delay with
+// the trendline estimator configured by opts. It fits a line to the smoothed
+// delay of the most recent groups of packets. This is the default.
+func WithTrendlineEstimator(opts ...TrendlineEstimatorOption) Option {
+	return func(c *Controller) error {
+		c.newDelayEstimator = func() (DelayEstimator, error) {
+			return newTrendlineEstimator(opts...)
+		}
+
+		return nil
+	}
+}
+
+// WithKalmanEstimator estimates the variation of the one way delay with the
+// Kalman filter described in draft-ietf-rmcat-gcc-02, configured by opts. It
+// tracks the delay variation as the state of a scalar Kalman filter, whose
+// gain adapts to the measured noise.
+func WithKalmanEstimator(opts ...KalmanEstimatorOption) Option {
+	return func(c *Controller) error {
+		c.newDelayEstimator = func() (DelayEstimator, error) {
+			return newKalmanEstimator(opts...)
+		}
+
+		return nil
+	}
+}
+
+// WithDelayEstimator estimates the variation of the one way delay with the
+// DelayEstimator returned by newEstimator, e.g. to compare custom filters. It
+// is called once per Controller, so estimators are not shared between
+// Controllers created from the same options. The estimator is Reset instead of
+// replaced when the arrival times jump.
+func WithDelayEstimator(newEstimator func() DelayEstimator) Option {
+	return func(c *Controller) error {
+		if newEstimator == nil {
+			return fmt.Errorf("%w: delay estimator factory must not be nil", errInvalidDelayEstimatorOption)
+		}
+		c.newDelayEstimator = func() (DelayEstimator, error) {
+			estimator := newEstimator()
+			if estimator == nil {
+				return nil, fmt.Errorf("%w: delay estimator factory returned nil", errInvalidDelayEstimatorOption)
+			}
+
+			return estimator, nil
+		}
+
+		return nil
+	}
+}
+
+// WithArrivalGroupOptions configures how acknowledged packets are grouped
+// before the delay between the groups is estimated, e.g. to limit the size of
+// groups on high bitrate links.
+func WithArrivalGroupOptions(opts ...ArrivalGroupOption) Option {
+	return func(c *Controller) error {
+		c.arrivalGroupOptions = append(c.arrivalGroupOptions, opts...)
+
+		return nil
+	}
+}
+
+// WithArrivalTimeResetHandler sets a callback which is called whenever the
+// arrival times reported by the remote peer jump, e.g. because the remote peer
+// reconnected. The delay-based estimation then starts over, while the target
+// bitrate is kept.
+func WithArrivalTimeResetHandler(handler func(reason ArrivalTimeResetReason)) Option {
+	return func(c *Controller) error {
+		c.onArrivalTimeReset = handler
+
+		return nil
+	}
+}
+
+// WithLossRateControllerOptions configures the default loss-based controller,
+// e.g. to react less to loss for screen sharing than for camera video. The
+// options are ignored if WithLossBasedBWEV2 is enabled.
+func WithLossRateControllerOptions(opts ...LossRateControllerOption) Option {
+	return func(c *Controller) error {
+		c.lossRateControllerOptions = append(c.lossRateControllerOptions, opts...)
+
+		return nil
+	}
+}
+
+// Controller implements the sender side of the Google Congestion Control
+// algorithm. It combines a delay-based estimate, which is derived from the
+// variation of the one way delay between groups of packets, with a loss-based
+// estimate, which is derived from the fraction of packets reported lost.
+//
+// Controller is not safe for concurrent use.
+type Controller struct {
+	initialBitrate int
+	minBitrate     int
+	maxBitrate     int
+	probing        bool
+
+	alrProbing             bool
+	alrIncreaseSuppression bool
+	bayesianAckedBitrate   bool
+	lossBasedBWEV2         bool
+
+	lossRateControllerOptions []LossRateControllerOption
+	arrivalGroupOptions       []ArrivalGroupOption
+	newDelayEstimator         func() (DelayEstimator, error)
+	onArrivalTimeReset        func(reason ArrivalTimeResetReason)
+
+	arrivalGroupAccumulator *arrivalGroupAccumulator
+	interArrival            *interArrival
+	delayEstimator          DelayEstimator
+	overuseDetector         *overuseDetector
+	ackedBitrateEstimator   ackedBitrateEstimator
+	rateController          *rateController
+	lossController          lossController
+	probeController         *probeController
+	probeBitrateEstimator   *probeBitrateEstimator
+	alrDetector             *alrDetector
+
+	probeClusters  map[int]ProbeCluster
+	state          state
+	pacerQueueSize int
+	targetBitrate  int
+}
+
+func newDefaultDelayEstimator() (DelayEstimator, error) {
+	return newTrendlineEstimator()
+}
+
+// NewController creates a new Controller configured by opts.
+func NewController(opts ...Option) (*Controller, error) {
+	controller := &Controller{
+		initialBitrate:            defaultInitialBitrate,
+		minBitrate:                defaultMinBitrate,
+		maxBitrate:                defaultMaxBitrate,
+		probing:                   true,
+		alrProbing:                true,
+		alrIncreaseSuppression:    false,
+		bayesianAckedBitrate:      false,
+		lossBasedBWEV2:            false,
+		lossRateControllerOptions: nil,
+		arrivalGroupOptions:       nil,
+		newDelayEstimator:         newDefaultDelayEstimator,
+		onArrivalTimeReset:        nil,
+		arrivalGroupAccumulator:   nil,
+		interArrival:              nil,
+		delayEstimator:            nil,
+		overuseDetector:           nil,
+		ackedBitrateEstimator:     nil,
+		rateController:            nil,
+		lossController:            nil,
+		probeController:           nil,
+		probeBitrateEstimator:     newProbeBitrateEstimator(),
+		alrDetector:               nil,
+		probeClusters:             map[int]ProbeCluster{},
+		state:                     stateIncrease,
+		pacerQueueSize:            0,
+		targetBitrate:             0,
+	}
+	for _, opt := range opts {
+		if err := opt(controller); err != nil {
+			return nil, err
+		}
+	}
+	if controller.minBitrate <= 0 {
+		return nil, fmt.Errorf("%w: min bitrate %d must be positive", errInvalidBitrate, controller.minBitrate)
+	}
+	if controller.minBitrate > controller.maxBitrate {
+		return nil, fmt.Errorf(
+			"%w: min bitrate %d exceeds max bitrate %d",
+			errInvalidBitrate, controller.minBitrate, controller.maxBitrate,
+		)
+	}
+	if controller.initialBitrate < controller.minBitrate || controller.initialBitrate > controller.maxBitrate {
+		return nil, fmt.Errorf(
+			"%w: initial bitrate %d outside of [%d, %d]",
+			errInvalidBitrate, controller.initialBitrate, controller.minBitrate, controller.maxBitrate,
+		)
+	}
+	controller.rateController = newRateController(
+		controller.initialBitrate,
+		controller.minBitrate,
+		controller.maxBitrate,
+	)
+	if controller.lossBasedBWEV2 {
+		controller.lossController = newLossBasedBWEV2(
+			controller.initialBitrate,
+			controller.minBitrate,
+			controller.maxBitrate,
+		)
+	} else {
+		lossRateController, err := newLossRateController(
+			controller.initialBitrate,
+			controller.minBitrate,
+			controller.maxBitrate,
+			controller.lossRateControllerOptions...,
+		)
+		if err != nil {
+			return nil, err
+		}
+		controller.lossController = lossRateController
+	}
+	arrivalGroupAccumulator, err := newArrivalGroupAccumulator(controller.arrivalGroupOptions...)
+	if err != nil {
+		return nil, err
+	}
+	controller.arrivalGroupAccumulator = arrivalGroupAccumulator
+	delayEstimator, err := controller.newDelayEstimator()
+	if err != nil {
+		return nil, err
+	}
+	controller.delayEstimator = delayEstimator
+	controller.overuseDetector = controller.newOveruseDetector()
+	controller.interArrival = newInterArrival(controller.resetDelayEstimation)
+	if controller.bayesianAckedBitrate {
+		controller.ackedBitrateEstimator = newBayesianAckedBitrateEstimator()
+	} else {
+		controller.ackedBitrateEstimator = newWindowedAckedBitrateEstimator(deliveryRateWindow)
+	}
+	controller.probeController = newProbeController(controller.maxBitrate)
+	controller.alrDetector = newALRDetector(controller.initialBitrate)
+	controller.targetBitrate = controller.initialBitrate
+
+	return controller, nil
+}
+
+// SetMaxBitrate changes the highest bitrate in bits per second the Controller
+// will ever return. A target bitrate above it is lowered immediately. If the
+// target bitrate was limited by the previous max bitrate, the new max bitrate
+// is probed.
+func (c *Controller) SetMaxBitrate(rate int) error {
+	if rate < c.minBitrate {
+		return fmt.Errorf("%w: min bitrate %d exceeds max bitrate %d", errInvalidBitrate, c.minBitrate, rate)
+	}
+	c.maxBitrate = rate
+	c.rateController.maxBitrate = rate
+	c.rateController.bitrate = min(c.rateController.bitrate, float64(rate))
+	c.lossController.setMaxBitrate(rate)
+	c.probeController.setMaxBitrate(rate, c.targetBitrate)
+	c.targetBitrate = min(c.targetBitrate, rate)
+
+	return nil
+}
+
+// OnPacketSent must be called for every packet sent, except padding, with its
+// size in bytes and the time it was sent. It is used to detect whether the
+// application is limited, i.e. sends less than the target bitrate allows.
+func (c *Controller) OnPacketSent(size int, departure time.Time) {
+	switch c.alrDetector.onPacketSent(size, departure) {
+	case alrEventStart:
+		if c.alrProbing {
+			c.probeController.setApplicationLimited(departure, true)
+		}
+	case alrEventEnd:
+		c.probeController.setApplicationLimited(departure, false)
+	case alrEventNone:
+	}
+}
+
+// ApplicationLimited returns whether the application currently sends less
+// than the target bitrate allows.
+func (c *Controller) ApplicationLimited() bool {
+	return c.alrDetector.inALR
+}
+
+// OnPacketAcked must be called for every packet that was reported as received
+// by the remote peer. sequenceNumber is a transport wide sequence number,
+// which increases by one for every packet sent, size the size of the packet in
+// bytes, departure the time the packet was sent and arrival the time the
+// remote peer received the packet. Departure and arrival times are not
+// required to be taken from synchronized clocks.
+func (c *Controller) OnPacketAcked(sequenceNumber uint64, size int, departure, arrival time.Time) {
+	c.ackedBitrateEstimator.onPacketAcked(arrival, size, c.alrDetector.inALR)
+	c.lossController.onPacketAcked(size, departure)
+
+	group := c.arrivalGroupAccumulator.onPacketAcked(sequenceNumber, size, departure, arrival)
+	if group == nil {
+		return
+	}
+	delta, ok := c.interArrival.onGroup(group)
+	if !ok {
+		return
+	}
+	trend := c.delayEstimator.Update(delta.lastArrival, delta.arrival-delta.departure)
+	c.state = c.state.transition(c.overuseDetector.update(delta.lastArrival, trend, delta.departure))
+}
+
+// resetDelayEstimation discards the delay accumulated by the delay estimator
+// and the state of the overuse detector after the arrival times jumped.
+func (c *Controller) resetDelayEstimation(reason ArrivalTimeResetReason) {
+	c.delayEstimator.Reset()
+	c.overuseDetector = c.newOveruseDetector()
+	if c.onArrivalTimeReset != nil {
+		c.onArrivalTimeReset(reason)
+	}
+}
+
+// newOveruseDetector returns an overuse detector which scales the estimate by
+// the number of groups only if it is the slope of the trendline estimator.
+func (c *Controller) newOveruseDetector() *overuseDetector {
+	_, trendline := c.delayEstimator.(*trendlineEstimator)
+
+	return newOveruseDetector(overuseDetectorScaleTrend(trendline))
+}
+
+// OnProbePacketAcked must be called in addition to OnPacketAcked for every
+// packet sent as part of the probe cluster with clusterID that was reported
+// as received by the remote peer. Once enough packets of a cluster were
+// acknowledged, the estimate is raised to the bitrate the probe showed the
+// path supports.
+func (c *Controller) OnProbePacketAcked(clusterID int, size int, departure, arrival time.Time) {
+	cluster, ok := c.probeClusters[clusterID]
+	if !ok {
+		return
+	}
+	bitrate, ok := c.probeBitrateEstimator.onProbePacketAcked(cluster, size, departure, arrival)
+	if !ok {
+		return
+	}
+	bitrate = min(bitrate, c.maxBitrate)
+	c.rateController.bitrate = max(c.rateController.bitrate, float64(bitrate))
+	c.lossController.onProbeResult(bitrate)
+	c.probeController.onProbeResult(bitrate)
+}
+
+// OnPacketLost must be called for every packet that was reported as lost by
+// the remote peer. size is the size of the packet in bytes and departure the
+// time it was sent.
+func (c *Controller) OnPacketLost(size int, departure time.Time) {
+	c.lossController.onPacketLost(size, departure)
+}
+
+// OnRTT must be called with every new round trip time measurement. The round
+// trip time determines how fast the bitrate is increased when it is close to
+// the capacity of the path.
+func (c *Controller) OnRTT(rtt time.Duration) {
+	c.rateController.onRTT(rtt)
+}
+
+// OnPacerQueue must be called with the number of bytes queued in the pacer, if
+// a pacer is used. The target bitrate is lowered by the rate needed to drain
+// the queue within a second, so the encoder does not keep adding to a queue
+// that built up, e.g. after the estimate dropped.
+func (c *Controller) OnPacerQueue(size int) {
+	c.pacerQueueSize = size
+}
+
+// Update combines the delay-based and the loss-based estimate into a new
+// target bitrate in bits per second and returns it. Update is expected to be
+// called once for every feedback report, after all packets included in the
+// report were passed to OnPacketAcked or OnPacketLost.
+func (c *Controller) Update(now time.Time) int {
+	deliveryRate, confidence := c.ackedBitrateEstimator.estimate()
+
+	s := c.state
+	if s == stateIncrease && c.alrIncreaseSuppression && c.alrDetector.inALR {
+		// The delivery rate only reflects what the application sent, so
+		// there is no evidence that the path supports a higher bitrate.
+		s = stateHold
+	}
+	delayBasedBitrate := c.rateController.update(now, s, deliveryRate, confidence)
+	lossBasedBitrate := c.lossController.update(deliveryRate, delayBasedBitrate)
+
+	target := min(delayBasedBitrate, lossBasedBitrate, c.maxBitrate)
+	target -= int(float64(8*c.pacerQueueSize) / pacerQueueDrainTime.Seconds())
+	c.targetBitrate = max(c.minBitrate, target)
+	c.alrDetector.setTargetBitrate(c.targetBitrate)
+
+	return c.targetBitrate
+}
+
+// ProbeClusters returns the probe clusters which should be sent at now. It is
+// expected to be called periodically by the pacer.
+func (c *Controller) ProbeClusters(now time.Time) []ProbeCluster {
+	if !c.probing {
+		return nil
+	}
+	clusters := c.probeController.process(now, c.targetBitrate)
+	for _, cluster := range clusters {
+		c.probeClusters[cluster.ID] = cluster
+		delete(c.probeClusters, cluster.ID-maxProbeClusters)
+	}
+
+	return clusters
+}
+
+// TargetBitrate returns the target bitrate in bits per second computed by the
+// most recent call to Update.
+func (c *Controller) TargetBitrate() int {
+	return c.targetBitrate
+}
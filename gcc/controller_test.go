@@ -0,0 +1,491 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewController(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{
+			name: "defaults",
+			opts: []Option{},
+			err:  nil,
+		},
+		{
+			name: "valid",
+			opts: []Option{
+				WithInitialBitrate(500_000),
+				WithMinBitrate(100_000),
+				WithMaxBitrate(1_000_000),
+			},
+			err: nil,
+		},
+		{
+			name: "zeroMin",
+			opts: []Option{WithMinBitrate(0)},
+			err:  errInvalidBitrate,
+		},
+		{
+			name: "minAboveMax",
+			opts: []Option{
+				WithInitialBitrate(500_000),
+				WithMinBitrate(2_000_000),
+				WithMaxBitrate(1_000_000),
+			},
+			err: errInvalidBitrate,
+		},
+		{
+			name: "initialBelowMin",
+			opts: []Option{
+				WithInitialBitrate(50_000),
+				WithMinBitrate(100_000),
+			},
+			err: errInvalidBitrate,
+		},
+		{
+			name: "initialAboveMax",
+			opts: []Option{
+				WithInitialBitrate(5_000_000),
+				WithMaxBitrate(1_000_000),
+			},
+			err: errInvalidBitrate,
+		},
+		{
+			name: "lossRateControllerOptions",
+			opts: []Option{
+				WithLossRateControllerOptions(LossRateControllerHighLossThreshold(0.2)),
+			},
+			err: nil,
+		},
+		{
+			name: "invalidLossRateControllerOptions",
+			opts: []Option{
+				WithLossRateControllerOptions(LossRateControllerMinPackets(0)),
+			},
+			err: errInvalidLossRateControllerOption,
+		},
+		{
+			name: "arrivalGroupOptions",
+			opts: []Option{
+				WithArrivalGroupOptions(
+					ArrivalGroupBurstRule(BurstGroupingArrival),
+					ArrivalGroupMaxSpan(50*time.Millisecond),
+				),
+			},
+			err: nil,
+		},
+		{
+			name: "invalidArrivalGroupOptions",
+			opts: []Option{
+				WithArrivalGroupOptions(ArrivalGroupMaxSpan(-1)),
+			},
+			err: errInvalidArrivalGroupOption,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewController(tc.opts...)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, c)
+
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.initialBitrate, c.TargetBitrate())
+		})
+	}
+}
+
+// feedController simulates a stream of packets of size bytes sent every
+// interval for duration, starting at start. queueing returns the additional
+// one way delay for the packet sent at time offset d, and lost reports
+// whether the packet with sequence number seq is lost. The controller is
+// updated every 100ms. feedController returns the time of the last update.
+func feedController(
+	t *testing.T,
+	c *Controller,
+	start time.Time,
+	duration, interval time.Duration,
+	size int,
+	queueing func(d time.Duration) time.Duration,
+	lost func(seq uint64) bool,
+) time.Time {
+	t.Helper()
+
+	seq := uint64(0)
+	nextUpdate := start.Add(100 * time.Millisecond)
+	now := start
+	for d := time.Duration(0); d < duration; d += interval {
+		now = start.Add(d)
+		if lost(seq) {
+			c.OnPacketLost(size, now)
+		} else {
+			c.OnPacketAcked(seq, size, now, now.Add(20*time.Millisecond+queueing(d)))
+		}
+		seq++
+		if !now.Before(nextUpdate) {
+			c.Update(now)
+			nextUpdate = nextUpdate.Add(100 * time.Millisecond)
+		}
+	}
+
+	return now
+}
+
+func TestControllerIncreasesWithoutCongestion(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(1_000_000))
+	assert.NoError(t, err)
+
+	// 1200 bytes every millisecond is a 9.6 Mbps stream, so the estimate is
+	// free to grow.
+	feedController(t, c, time.Time{}.Add(time.Second), 5*time.Second, time.Millisecond, 1200,
+		func(time.Duration) time.Duration { return 0 },
+		func(uint64) bool { return false },
+	)
+	assert.Greater(t, c.TargetBitrate(), 1_000_000)
+	assert.LessOrEqual(t, c.TargetBitrate(), c.maxBitrate)
+}
+
+func TestControllerDecreasesOnDelayIncrease(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(5_000_000))
+	assert.NoError(t, err)
+
+	start := time.Time{}.Add(time.Second)
+	// The queue grows by 100 microseconds for every millisecond sent, which
+	// means the stream is sent about 10% faster than the bottleneck can
+	// forward it.
+	feedController(t, c, start, 2*time.Second, time.Millisecond, 300,
+		func(d time.Duration) time.Duration { return d / 10 },
+		func(uint64) bool { return false },
+	)
+	assert.Equal(t, stateDecrease, c.state)
+	assert.Less(t, c.TargetBitrate(), 5_000_000)
+}
+
+func TestControllerDecreasesOnLoss(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(1_000_000), WithMinBitrate(100_000))
+	assert.NoError(t, err)
+
+	// Every fifth packet is lost, which is well above the loss threshold of
+	// the loss-based controller.
+	feedController(t, c, time.Time{}.Add(time.Second), time.Second, time.Millisecond, 1200,
+		func(time.Duration) time.Duration { return 0 },
+		func(seq uint64) bool { return seq%5 == 0 },
+	)
+	assert.Less(t, c.TargetBitrate(), 1_000_000)
+	assert.GreaterOrEqual(t, c.TargetBitrate(), 100_000)
+}
+
+func TestControllerLeavesRoomToDrainPacerQueue(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(1_000_000), WithMinBitrate(100_000))
+	assert.NoError(t, err)
+	now := time.Time{}.Add(time.Second)
+
+	assert.Equal(t, 1_000_000, c.Update(now))
+	// 50 kB drain in a second at 400 kbps.
+	c.OnPacerQueue(50_000)
+	assert.Equal(t, 600_000, c.Update(now))
+	c.OnPacerQueue(500_000)
+	assert.Equal(t, 100_000, c.Update(now))
+	c.OnPacerQueue(0)
+	assert.Equal(t, 1_000_000, c.Update(now))
+}
+
+func TestControllerProbing(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(300_000))
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	clusters := c.ProbeClusters(start)
+	assert.Equal(t, []int{900_000, 1_800_000}, probeBitrates(clusters))
+
+	// The path delivers the first cluster at the probed bitrate.
+	cluster := clusters[0]
+	interval := time.Duration(float64(time.Second) * 8 * 1000 / float64(cluster.Bitrate))
+	for i := range max(cluster.MinPackets, cluster.MinBytes/1000+1) {
+		departure := start.Add(time.Duration(i) * interval)
+		arrival := departure.Add(20 * time.Millisecond)
+		c.OnPacketAcked(uint64(i), 1000, departure, arrival) // nolint:gosec
+		c.OnProbePacketAcked(cluster.ID, 1000, departure, arrival)
+	}
+	// Packets of unknown clusters are ignored.
+	c.OnProbePacketAcked(100, 1000, start, start.Add(time.Second))
+
+	target := c.Update(start.Add(100 * time.Millisecond))
+	assert.InDelta(t, 900_000, target, 10_000)
+}
+
+func TestControllerWithoutProbing(t *testing.T) {
+	c, err := NewController(WithProbing(false))
+	assert.NoError(t, err)
+	assert.Empty(t, c.ProbeClusters(time.Time{}.Add(time.Second)))
+}
+
+func TestControllerSetMaxBitrate(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(1_000_000), WithMinBitrate(100_000), WithMaxBitrate(1_000_000))
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+	assert.Equal(t, []int{1_000_000}, probeBitrates(c.ProbeClusters(start)))
+	assert.Empty(t, c.ProbeClusters(start.Add(2*time.Second)))
+
+	assert.ErrorIs(t, c.SetMaxBitrate(50_000), errInvalidBitrate)
+
+	// The target bitrate is at the max bitrate, so the new max is probed.
+	assert.NoError(t, c.SetMaxBitrate(3_000_000))
+	assert.Equal(t, []int{3_000_000}, probeBitrates(c.ProbeClusters(start.Add(3*time.Second))))
+
+	// A target bitrate above the new max is lowered before the next update.
+	assert.NoError(t, c.SetMaxBitrate(500_000))
+	assert.Equal(t, 500_000, c.TargetBitrate())
+	assert.LessOrEqual(t, c.rateController.bitrate, 500_000.0)
+	lossController, ok := c.lossController.(*lossRateController)
+	assert.True(t, ok)
+	assert.LessOrEqual(t, lossController.bitrate, 500_000)
+	assert.Equal(t, 500_000, c.Update(start.Add(4*time.Second)))
+}
+
+func TestControllerApplicationLimited(t *testing.T) {
+	cases := []struct {
+		name                  string
+		opts                  []Option
+		expectedProbes        []int
+		expectedIncreaseInALR bool
+	}{
+		{
+			name:                  "default",
+			opts:                  []Option{},
+			expectedProbes:        []int{2_000_000},
+			expectedIncreaseInALR: true,
+		},
+		{
+			name:                  "withoutALRProbing",
+			opts:                  []Option{WithALRProbing(false)},
+			expectedProbes:        []int{},
+			expectedIncreaseInALR: true,
+		},
+		{
+			name:                  "withIncreaseSuppression",
+			opts:                  []Option{WithALRIncreaseSuppression(true)},
+			expectedProbes:        []int{2_000_000},
+			expectedIncreaseInALR: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewController(append([]Option{WithInitialBitrate(1_000_000)}, tc.opts...)...)
+			assert.NoError(t, err)
+			start := time.Time{}.Add(time.Second)
+			assert.NotEmpty(t, c.ProbeClusters(start))
+			assert.Empty(t, c.ProbeClusters(start.Add(2*time.Second)))
+
+			// 100 kbps is a tenth of the target bitrate.
+			now := start
+			for ; now.Before(start.Add(8 * time.Second)); now = now.Add(10 * time.Millisecond) {
+				c.OnPacketSent(125, now)
+			}
+			assert.True(t, c.ApplicationLimited())
+			assert.Equal(t, tc.expectedProbes, probeBitrates(c.ProbeClusters(now)))
+
+			// Without acknowledged packets the loss-based estimate doesn't
+			// grow, so only the delay-based estimate can be observed.
+			c.Update(now)
+			before := c.rateController.bitrate
+			c.Update(now.Add(time.Second))
+			after := c.rateController.bitrate
+			if tc.expectedIncreaseInALR {
+				assert.Greater(t, after, before)
+			} else {
+				assert.Equal(t, before, after)
+			}
+
+			for ; now.Before(start.Add(10 * time.Second)); now = now.Add(10 * time.Millisecond) {
+				c.OnPacketSent(1250, now)
+			}
+			assert.False(t, c.ApplicationLimited())
+		})
+	}
+}
+
+func TestControllerWithBayesianAckedBitrateEstimator(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(5_000_000), WithBayesianAckedBitrateEstimator(true))
+	assert.NoError(t, err)
+	_, ok := c.ackedBitrateEstimator.(*bayesianAckedBitrateEstimator)
+	assert.True(t, ok)
+
+	feedController(t, c, time.Time{}.Add(time.Second), 2*time.Second, time.Millisecond, 300,
+		func(d time.Duration) time.Duration { return d / 10 },
+		func(uint64) bool { return false },
+	)
+	assert.Equal(t, stateDecrease, c.state)
+	assert.Less(t, c.TargetBitrate(), 5_000_000)
+}
+
+func TestControllerWithLossBasedBWEV2(t *testing.T) {
+	// 300 bytes every 10ms is a 240 kbps stream, of which every eighth packet
+	// is lost regardless of the bitrate.
+	randomLoss := func(seq uint64) bool { return seq%8 == 7 }
+
+	c, err := NewController(WithInitialBitrate(300_000))
+	assert.NoError(t, err)
+	feedController(t, c, time.Time{}.Add(time.Second), 10*time.Second, 10*time.Millisecond, 300,
+		func(time.Duration) time.Duration { return 0 },
+		randomLoss,
+	)
+	assert.Less(t, c.TargetBitrate(), 100_000)
+
+	c, err = NewController(WithInitialBitrate(300_000), WithLossBasedBWEV2(true))
+	assert.NoError(t, err)
+	_, ok := c.lossController.(*lossBasedBWEV2)
+	assert.True(t, ok)
+	feedController(t, c, time.Time{}.Add(time.Second), 10*time.Second, 10*time.Millisecond, 300,
+		func(time.Duration) time.Duration { return 0 },
+		randomLoss,
+	)
+	// The loss is explained by the path at the only sending rate observed, so
+	// the estimate settles at the delivery rate instead of collapsing.
+	assert.GreaterOrEqual(t, c.TargetBitrate(), 200_000)
+}
+
+func TestControllerWithKalmanEstimator(t *testing.T) {
+	c, err := NewController(WithInitialBitrate(1_000_000), WithKalmanEstimator())
+	assert.NoError(t, err)
+	_, ok := c.delayEstimator.(*kalmanEstimator)
+	assert.True(t, ok)
+	assert.False(t, c.overuseDetector.scaleTrend)
+
+	// The delay is flat apart from up to 20ms of jitter, which the filter
+	// attributes to noise. Scaled by the number of groups, the remaining
+	// offset would exceed the threshold.
+	rng := rand.New(rand.NewPCG(1, 2)) // nolint:gosec
+	start := time.Time{}.Add(time.Second)
+	now := feedController(t, c, start, 3*time.Second, time.Millisecond, 300,
+		func(time.Duration) time.Duration { return time.Duration(rng.Int64N(int64(20 * time.Millisecond))) },
+		func(uint64) bool { return false },
+	)
+	assert.Equal(t, usageNormal, c.overuseDetector.usage)
+	assert.NotEqual(t, stateDecrease, c.state)
+	assert.GreaterOrEqual(t, c.TargetBitrate(), 1_000_000)
+
+	// The stream is sent three times faster than the bottleneck forwards it,
+	// so the delay of every group of 5ms grows by 10ms, above the threshold.
+	feedController(t, c, now.Add(time.Millisecond), 2*time.Second, time.Millisecond, 300,
+		func(d time.Duration) time.Duration { return 2 * d },
+		func(uint64) bool { return false },
+	)
+	assert.Equal(t, stateDecrease, c.state)
+	assert.Less(t, c.TargetBitrate(), 1_000_000)
+}
+
+// constantDelayEstimator is a custom DelayEstimator which always reports
+// the same estimate.
+type constantDelayEstimator struct {
+	estimate float64
+	updates  int
+	resets   int
+}
+
+func (e *constantDelayEstimator) Update(time.Time, time.Duration) float64 {
+	e.updates++
+
+	return e.estimate
+}
+
+func (e *constantDelayEstimator) Reset() {
+	e.resets++
+}
+
+func TestControllerWithDelayEstimator(t *testing.T) {
+	estimators := []*constantDelayEstimator{}
+	newEstimator := func() DelayEstimator {
+		estimators = append(estimators, &constantDelayEstimator{estimate: 20, updates: 0, resets: 0})
+
+		return estimators[len(estimators)-1]
+	}
+	c, err := NewController(WithInitialBitrate(5_000_000), WithDelayEstimator(newEstimator))
+	assert.NoError(t, err)
+	_, err = NewController(WithDelayEstimator(newEstimator))
+	assert.NoError(t, err)
+	assert.Len(t, estimators, 2)
+
+	// Without any queueing, the custom estimator still signals overuse.
+	feedController(t, c, time.Time{}.Add(time.Second), 2*time.Second, time.Millisecond, 300,
+		func(time.Duration) time.Duration { return 0 },
+		func(uint64) bool { return false },
+	)
+	assert.Positive(t, estimators[0].updates)
+	assert.Zero(t, estimators[1].updates)
+	assert.Equal(t, stateDecrease, c.state)
+
+	// A jump of the arrival times resets the estimator instead of creating a
+	// new one.
+	feedController(t, c, time.Time{}.Add(time.Minute), time.Second, time.Millisecond, 300,
+		func(time.Duration) time.Duration { return 10 * time.Second },
+		func(uint64) bool { return false },
+	)
+	assert.Len(t, estimators, 2)
+	assert.Equal(t, 1, estimators[0].resets)
+}
+
+func TestControllerInvalidDelayEstimatorOptions(t *testing.T) {
+	_, err := NewController(WithTrendlineEstimator(TrendlineWindowSize(0)))
+	assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+	_, err = NewController(WithKalmanEstimator(KalmanChi(2)))
+	assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+
+	c, err := NewController(WithTrendlineEstimator(TrendlineWindowSize(20), TrendlineThresholdGain(2)))
+	assert.NoError(t, err)
+	te, ok := c.delayEstimator.(*trendlineEstimator)
+	assert.True(t, ok)
+	assert.Equal(t, 20, te.windowSize)
+	assert.InDelta(t, 2.0, te.thresholdGain, 0)
+	assert.True(t, c.overuseDetector.scaleTrend)
+
+	c, err = NewController(WithDelayEstimator(nil))
+	assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+	assert.Nil(t, c)
+	c, err = NewController(WithDelayEstimator(func() DelayEstimator { return nil }))
+	assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+	assert.Nil(t, c)
+}
+
+func TestControllerArrivalTimeReset(t *testing.T) {
+	var resets []ArrivalTimeResetReason
+	c, err := NewController(
+		WithInitialBitrate(1_000_000),
+		WithArrivalTimeResetHandler(func(reason ArrivalTimeResetReason) {
+			resets = append(resets, reason)
+		}),
+	)
+	assert.NoError(t, err)
+
+	// The arrival clock of the remote peer jumps forward by 10 seconds after
+	// one second, which must not be mistaken for queueing.
+	feedController(t, c, time.Time{}.Add(time.Second), 3*time.Second, time.Millisecond, 1200,
+		func(d time.Duration) time.Duration {
+			if d >= time.Second {
+				return 10 * time.Second
+			}
+
+			return 0
+		},
+		func(uint64) bool { return false },
+	)
+	assert.Equal(t, []ArrivalTimeResetReason{ArrivalTimeJumpedForward}, resets)
+	assert.NotEqual(t, stateDecrease, c.state)
+	assert.Greater(t, c.TargetBitrate(), 1_000_000)
+	te, ok := c.delayEstimator.(*trendlineEstimator)
+	assert.True(t, ok)
+	assert.Less(t, te.accumulatedDelay, time.Second)
+}
@@ -0,0 +1,383 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/pion/rtcp"
+)
+
+const (
+	// twccReferenceTimeUnit is the resolution of the reference time in
+	// transport wide congestion control feedback.
+	twccReferenceTimeUnit = 64 * time.Millisecond
+
+	// ccfbArrivalTimeOffsetUnavailable is the arrival time offset reported in
+	// RFC 8888 feedback if the arrival time of a packet is unknown.
+	ccfbArrivalTimeOffsetUnavailable = 0x1FFF
+
+	// lossReorderWindow is how long after a packet was first reported lost
+	// the feedback adapter waits for feedback reporting it received, e.g.
+	// because it was reordered, before it reports the loss.
+	lossReorderWindow = 100 * time.Millisecond
+)
+
+// packetResult reports the outcome of a single packet. Packets which were
+// received but whose arrival time is unknown have a zero arrival time.
+type packetResult struct {
+	sequenceNumber uint64
+	size           int
+	departure      time.Time
+	arrival        time.Time
+	received       bool
+	flags          sentPacketFlags
+	probeClusterID int
+}
+
+// missingPacket is a packet which was reported lost at reported.
+type missingPacket struct {
+	sequenceNumber uint64
+	reported       time.Time
+}
+
+// feedbackAdapter assigns a transport wide sequence number to every outgoing
+// packet and maps the 16 bit sequence numbers in TWCC and RFC 8888 feedback
+// back to the packets that were sent. The transport wide sequence numbers are
+// the ones sent in the TWCC header extension, so TWCC feedback is looked up in
+// the history directly, and RFC 8888 feedback through the rtpIndex of the
+// SSRC. It reports every packet at most once,
+// either as received or as lost: Duplicate feedback is ignored, and a packet
+// reported lost is only reported as lost if no feedback reported it received
+// within lossReorderWindow. Feedback reporting it received even later is
+// ignored, so reordering never counts a packet as both lost and received.
+type feedbackAdapter struct {
+	history    *sendHistory
+	missing    []missingPacket
+	rtpIndices map[uint32]*rtpIndex
+
+	twccReferenceTime *unwrapper
+	ccfbReportTime    *unwrapper
+}
+
+func newFeedbackAdapter() *feedbackAdapter {
+	return &feedbackAdapter{
+		history:           newSendHistory(sendHistoryMaxAge, maxSentPackets),
+		missing:           nil,
+		rtpIndices:        map[uint32]*rtpIndex{},
+		twccReferenceTime: newUnwrapper(24),
+		ccfbReportTime:    newUnwrapper(32),
+	}
+}
+
+// nextSequenceNumber returns the transport wide sequence number the next
+// packet passed to onPacketSent is assigned.
+func (a *feedbackAdapter) nextSequenceNumber() uint64 {
+	return a.history.next
+}
+
+// onPacketSent adds a packet to the history and returns its transport wide
+// sequence number. If hasTWCC is true, the lowest 16 bits of the sequence
+// number must be sent in the TWCC header extension of the packet, otherwise
+// the packet can only be acknowledged by RFC 8888 feedback. probeClusterID is
+// only used if flags mark the packet as a probe.
+func (a *feedbackAdapter) onPacketSent(
+	ssrc uint32,
+	rtpSequenceNumber uint16,
+	hasTWCC bool,
+	size int,
+	departure time.Time,
+	flags sentPacketFlags,
+	probeClusterID int,
+) uint64 {
+	index, ok := a.rtpIndices[ssrc]
+	if !ok {
+		index = newRTPIndex(maxSentPackets)
+		a.rtpIndices[ssrc] = index
+	}
+	packet := sentPacket{
+		sequenceNumber:    0,
+		ssrc:              ssrc,
+		rtpSequenceNumber: index.unwrapper.unwrap(uint64(rtpSequenceNumber)),
+		hasTWCC:           hasTWCC,
+		size:              size,
+		departure:         departure,
+		flags:             flags,
+		probeClusterID:    probeClusterID,
+		status:            feedbackStatusNone,
+	}
+	sequenceNumber := a.history.add(packet)
+	index.add(packet.rtpSequenceNumber, sequenceNumber)
+
+	return sequenceNumber
+}
+
+// onFeedback processes all TWCC and RFC 8888 feedback in pkts, which arrived
+// at now. It returns the results of all packets reported received in the
+// feedback and of all packets whose reorder window ended, ordered by transport
+// wide sequence number, and the shortest round trip time measured for any of
+// the received packets. If no round trip time could be measured, the returned
+// round trip time is 0.
+func (a *feedbackAdapter) onFeedback(now time.Time, pkts []rtcp.Packet) ([]packetResult, time.Duration) {
+	results := []packetResult{}
+	rtt := time.Duration(math.MaxInt64)
+	for _, pkt := range pkts {
+		var res []packetResult
+		var ackDelay time.Duration
+		switch fb := pkt.(type) {
+		case *rtcp.TransportLayerCC:
+			res = a.onTransportCC(now, fb)
+		case *rtcp.CCFeedbackReport:
+			res, ackDelay = a.onCCFB(now, fb)
+		default:
+			continue
+		}
+		for _, r := range res {
+			if r.received {
+				rtt = min(rtt, now.Sub(r.departure)-ackDelay)
+			}
+		}
+		results = append(results, res...)
+	}
+	if rtt == math.MaxInt64 || rtt < 0 {
+		rtt = 0
+	}
+
+	return a.completeResults(now, results), rtt
+}
+
+// onReport processes a report of an rtpfb.Interceptor on feedback which
+// arrived at now. The packet reports are matched to the packets in the history
+// by their SSRC and RTP sequence number, and reported like the packets in the
+// feedback passed to onFeedback. The returned round trip time is the one of
+// the report, or 0 if the report acknowledges no packet.
+func (a *feedbackAdapter) onReport(now time.Time, report rtpfb.Report) ([]packetResult, time.Duration) {
+	results := []packetResult{}
+	acked := false
+	for _, pr := range report.PacketReports {
+		index, ok := a.rtpIndices[pr.SSRC]
+		if !ok {
+			continue
+		}
+		rtpSequenceNumber := unwrapAt(index.unwrapper.last, uint64(pr.RTPSequenceNumber), 16)
+		packet, found := a.lookupRTP(pr.SSRC, index, rtpSequenceNumber)
+		if !pr.Arrived {
+			a.onMissing(now, packet, found)
+
+			continue
+		}
+		acked = true
+		if result, ok := a.onReceived(packet, found, pr.Arrival); ok {
+			results = append(results, result)
+		}
+	}
+	rtt := report.RTT
+	if !acked || rtt < 0 {
+		rtt = 0
+	}
+
+	return a.completeResults(now, results), rtt
+}
+
+// completeResults adds the results of the packets whose reorder window ended
+// at now to results and sorts them by transport wide sequence number.
+func (a *feedbackAdapter) completeResults(now time.Time, results []packetResult) []packetResult {
+	results = a.appendLosses(now, results)
+	slices.SortStableFunc(results, func(x, y packetResult) int {
+		return cmp.Compare(x.sequenceNumber, y.sequenceNumber)
+	})
+
+	return results
+}
+
+//nolint:cyclop
+func (a *feedbackAdapter) onTransportCC(now time.Time, fb *rtcp.TransportLayerCC) []packetResult {
+	results := []packetResult{}
+	if a.history.len() == 0 {
+		// Nothing was sent, so the feedback can't be matched.
+		return results
+	}
+	reference := time.Time{}.Add(
+		time.Duration(a.twccReferenceTime.unwrap(uint64(fb.ReferenceTime))) * twccReferenceTimeUnit,
+	)
+	arrival := reference
+	deltaIndex := 0
+	offset := uint16(0)
+
+	onSymbol := func(symbol uint16) {
+		twccSequenceNumber := fb.BaseSequenceNumber + offset
+		offset++
+		var result packetResult
+		var ok bool
+		switch symbol {
+		case rtcp.TypeTCCPacketNotReceived:
+			packet, found := a.lookupTWCC(twccSequenceNumber)
+			a.onMissing(now, packet, found)
+		case rtcp.TypeTCCPacketReceivedSmallDelta, rtcp.TypeTCCPacketReceivedLargeDelta:
+			if deltaIndex >= len(fb.RecvDeltas) {
+				return
+			}
+			arrival = arrival.Add(time.Duration(fb.RecvDeltas[deltaIndex].Delta) * time.Microsecond)
+			deltaIndex++
+			packet, found := a.lookupTWCC(twccSequenceNumber)
+			result, ok = a.onReceived(packet, found, arrival)
+		case rtcp.TypeTCCPacketReceivedWithoutDelta:
+			packet, found := a.lookupTWCC(twccSequenceNumber)
+			result, ok = a.onReceived(packet, found, time.Time{})
+		}
+		if ok {
+			results = append(results, result)
+		}
+	}
+
+	for _, chunk := range fb.PacketChunks {
+		switch c := chunk.(type) {
+		case *rtcp.RunLengthChunk:
+			for i := uint16(0); i < c.RunLength && offset < fb.PacketStatusCount; i++ {
+				onSymbol(c.PacketStatusSymbol)
+			}
+		case *rtcp.StatusVectorChunk:
+			// The last chunk may be padded with symbols for packets that are
+			// not included in the feedback.
+			for _, symbol := range c.SymbolList {
+				if offset >= fb.PacketStatusCount {
+					break
+				}
+				onSymbol(symbol)
+			}
+		}
+	}
+
+	return results
+}
+
+// onCCFB returns the results of the packets reported received in fb and the
+// time the receiver held the latest packet before sending fb.
+func (a *feedbackAdapter) onCCFB(now time.Time, fb *rtcp.CCFeedbackReport) ([]packetResult, time.Duration) {
+	results := []packetResult{}
+	// The report timestamp is the middle 32 bits of an NTP timestamp, i.e.
+	// 16 bits of seconds and 16 bits of fractions of a second.
+	reference := time.Time{}.Add(
+		time.Duration(a.ccfbReportTime.unwrap(uint64(fb.ReportTimestamp))) * time.Second / (1 << 16),
+	)
+	latestArrival := time.Time{}
+	for _, block := range fb.ReportBlocks {
+		index, ok := a.rtpIndices[block.MediaSSRC]
+		if !ok {
+			continue
+		}
+		for i, metric := range block.MetricBlocks {
+			rtpSequenceNumber := unwrapAt(index.unwrapper.last, uint64(block.BeginSequence)+uint64(i), 16)
+			packet, found := a.lookupRTP(block.MediaSSRC, index, rtpSequenceNumber)
+			var result packetResult
+			switch {
+			case !metric.Received:
+				a.onMissing(now, packet, found)
+
+				continue
+			case metric.ArrivalTimeOffset == ccfbArrivalTimeOffsetUnavailable:
+				result, ok = a.onReceived(packet, found, time.Time{})
+			default:
+				arrival := reference.Add(-time.Duration(metric.ArrivalTimeOffset) * time.Second / 1024)
+				if arrival.After(latestArrival) {
+					latestArrival = arrival
+				}
+				result, ok = a.onReceived(packet, found, arrival)
+			}
+			if ok {
+				results = append(results, result)
+			}
+		}
+	}
+	ackDelay := time.Duration(0)
+	if !latestArrival.IsZero() {
+		ackDelay = reference.Sub(latestArrival)
+	}
+
+	return results, ackDelay
+}
+
+// lookupTWCC returns the packet sent with twccSequenceNumber in its TWCC
+// header extension, which is unwrapped relative to the last packet sent.
+func (a *feedbackAdapter) lookupTWCC(twccSequenceNumber uint16) (*sentPacket, bool) {
+	sequenceNumber := unwrapAt(int64(a.history.next)-1, uint64(twccSequenceNumber), 16) // nolint:gosec
+	if sequenceNumber < 0 {
+		return nil, false
+	}
+	packet, found := a.history.get(uint64(sequenceNumber))
+	if !found || !packet.hasTWCC {
+		return nil, false
+	}
+
+	return packet, true
+}
+
+// lookupRTP returns the packet sent on ssrc with the unwrapped
+// rtpSequenceNumber.
+func (a *feedbackAdapter) lookupRTP(ssrc uint32, index *rtpIndex, rtpSequenceNumber int64) (*sentPacket, bool) {
+	packet, found := a.history.get(index.get(rtpSequenceNumber))
+	if !found || packet.ssrc != ssrc || packet.rtpSequenceNumber != rtpSequenceNumber {
+		return nil, false
+	}
+
+	return packet, true
+}
+
+// onMissing starts the reorder window of packet, which was reported lost at
+// now, if it was not reported before.
+func (a *feedbackAdapter) onMissing(now time.Time, packet *sentPacket, found bool) {
+	if !found || packet.status != feedbackStatusNone {
+		return
+	}
+	packet.status = feedbackStatusMissing
+	a.missing = append(a.missing, missingPacket{sequenceNumber: packet.sequenceNumber, reported: now})
+}
+
+// appendLosses appends the results of the missing packets whose reorder
+// window ended at now to results.
+func (a *feedbackAdapter) appendLosses(now time.Time, results []packetResult) []packetResult {
+	i := 0
+	for ; i < len(a.missing) && now.Sub(a.missing[i].reported) >= lossReorderWindow; i++ {
+		packet, found := a.history.get(a.missing[i].sequenceNumber)
+		if !found || packet.status != feedbackStatusMissing {
+			continue
+		}
+		packet.status = feedbackStatusLost
+		results = append(results, packetResult{
+			sequenceNumber: packet.sequenceNumber,
+			size:           packet.size,
+			departure:      packet.departure,
+			arrival:        time.Time{},
+			received:       false,
+			flags:          packet.flags,
+			probeClusterID: packet.probeClusterID,
+		})
+	}
+	a.missing = a.missing[i:]
+
+	return results
+}
+
+// onReceived returns a result for packet if it was neither reported as
+// received nor as lost before.
+func (a *feedbackAdapter) onReceived(packet *sentPacket, found bool, arrival time.Time) (packetResult, bool) {
+	if !found || packet.status == feedbackStatusReceived || packet.status == feedbackStatusLost {
+		return packetResult{}, false
+	}
+	packet.status = feedbackStatusReceived
+
+	return packetResult{
+		sequenceNumber: packet.sequenceNumber,
+		size:           packet.size,
+		departure:      packet.departure,
+		arrival:        arrival,
+		received:       true,
+		flags:          packet.flags,
+		probeClusterID: packet.probeClusterID,
+	}, true
+}
@@ -0,0 +1,348 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func twccFeedback(base uint16, count uint16, symbols []uint16, deltas []time.Duration) *rtcp.TransportLayerCC {
+	recvDeltas := make([]*rtcp.RecvDelta, 0, len(deltas))
+	for _, d := range deltas {
+		recvDeltas = append(recvDeltas, &rtcp.RecvDelta{
+			Type:  rtcp.TypeTCCPacketReceivedSmallDelta,
+			Delta: d.Microseconds(),
+		})
+	}
+
+	return &rtcp.TransportLayerCC{
+		BaseSequenceNumber: base,
+		PacketStatusCount:  count,
+		ReferenceTime:      1,
+		PacketChunks: []rtcp.PacketStatusChunk{
+			&rtcp.StatusVectorChunk{
+				SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+				SymbolList: symbols,
+			},
+		},
+		RecvDeltas: recvDeltas,
+	}
+}
+
+func TestFeedbackAdapterTWCC(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	reference := time.Time{}.Add(twccReferenceTimeUnit)
+	cases := []struct {
+		name     string
+		first    uint64
+		sent     int
+		feedback []*rtcp.TransportLayerCC
+		expected [][]packetResult
+		lost     []packetResult
+	}{
+		{
+			// The first packet was sent without TWCC header extension.
+			name:  "unknownPackets",
+			first: 1,
+			sent:  0,
+			feedback: []*rtcp.TransportLayerCC{
+				twccFeedback(0, 1, []uint16{rtcp.TypeTCCPacketReceivedSmallDelta}, []time.Duration{0}),
+			},
+			expected: [][]packetResult{{}},
+			lost:     []packetResult{},
+		},
+		{
+			name:  "wrapAround",
+			first: 65534,
+			sent:  4,
+			feedback: []*rtcp.TransportLayerCC{
+				twccFeedback(65534, 4, []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketReceivedWithoutDelta,
+					// padding
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketNotReceived,
+				}, []time.Duration{time.Millisecond, 2 * time.Millisecond}),
+			},
+			expected: [][]packetResult{{
+				{sequenceNumber: 65534, size: 1200, departure: start, arrival: reference.Add(time.Millisecond), received: true},
+				{
+					sequenceNumber: 65536, size: 1200, departure: start.Add(2 * time.Millisecond),
+					arrival: reference.Add(3 * time.Millisecond), received: true,
+				},
+				{sequenceNumber: 65537, size: 1200, departure: start.Add(3 * time.Millisecond), received: true},
+			}},
+			lost: []packetResult{{sequenceNumber: 65535, size: 1200, departure: start.Add(time.Millisecond)}},
+		},
+		{
+			name:  "duplicateFeedback",
+			first: 7,
+			sent:  2,
+			feedback: []*rtcp.TransportLayerCC{
+				twccFeedback(7, 2, []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+				}, []time.Duration{time.Millisecond}),
+				twccFeedback(7, 2, []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+					rtcp.TypeTCCPacketNotReceived,
+				}, []time.Duration{time.Millisecond}),
+			},
+			expected: [][]packetResult{
+				{
+					{sequenceNumber: 7, size: 1200, departure: start, arrival: reference.Add(time.Millisecond), received: true},
+				},
+				{},
+			},
+			lost: []packetResult{{sequenceNumber: 8, size: 1200, departure: start.Add(time.Millisecond)}},
+		},
+		{
+			name:  "lateArrival",
+			first: 7,
+			sent:  2,
+			feedback: []*rtcp.TransportLayerCC{
+				twccFeedback(7, 2, []uint16{
+					rtcp.TypeTCCPacketNotReceived,
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+				}, []time.Duration{time.Millisecond}),
+				twccFeedback(7, 1, []uint16{
+					rtcp.TypeTCCPacketReceivedSmallDelta,
+				}, []time.Duration{2 * time.Millisecond}),
+			},
+			// The packet reported lost first is reported received within the
+			// reorder window, so it is never reported lost.
+			expected: [][]packetResult{
+				{
+					{
+						sequenceNumber: 8, size: 1200, departure: start.Add(time.Millisecond),
+						arrival: reference.Add(time.Millisecond), received: true,
+					},
+				},
+				{
+					{sequenceNumber: 7, size: 1200, departure: start, arrival: reference.Add(2 * time.Millisecond), received: true},
+				},
+			},
+			lost: []packetResult{},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			fa := newFeedbackAdapter()
+			for range tc.first {
+				fa.onPacketSent(1, 0, false, 1200, start, 0, 0)
+			}
+			for i := range tc.sent {
+				fa.onPacketSent(2, uint16(i), true, 1200, start.Add(time.Duration(i)*time.Millisecond), 0, 0) // nolint:gosec
+			}
+			now := start.Add(time.Second)
+			for i, fb := range tc.feedback {
+				results, _ := fa.onFeedback(now, []rtcp.Packet{fb})
+				assert.Equal(t, tc.expected[i], results)
+			}
+			results, _ := fa.onFeedback(now.Add(lossReorderWindow), nil)
+			assert.Equal(t, tc.lost, results)
+		})
+	}
+}
+
+func TestFeedbackAdapterCCFB(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	fa := newFeedbackAdapter()
+	// Two streams, sent alternately, the first one wraps around.
+	for i := range 4 {
+		departure := start.Add(time.Duration(2*i) * time.Millisecond)
+		fa.onPacketSent(1, uint16(65534+i), false, 1000, departure, 0, 0)                    // nolint:gosec
+		fa.onPacketSent(2, uint16(100+i), false, 500, departure.Add(time.Millisecond), 0, 0) // nolint:gosec
+	}
+	// The report was sent 1s after the reference time 0. The arrival time
+	// offsets are in units of 1/1024 seconds.
+	report := &rtcp.CCFeedbackReport{
+		ReportTimestamp: 1 << 16,
+		ReportBlocks: []rtcp.CCFeedbackReportBlock{
+			{
+				MediaSSRC:     2,
+				BeginSequence: 101,
+				MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 256},
+					{Received: false},
+				},
+			},
+			{
+				MediaSSRC:     1,
+				BeginSequence: 65535,
+				MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 512},
+					{Received: true, ArrivalTimeOffset: ccfbArrivalTimeOffsetUnavailable},
+				},
+			},
+			{
+				MediaSSRC:     3,
+				BeginSequence: 0,
+				MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 0},
+				},
+			},
+		},
+	}
+	now := start.Add(time.Second)
+	results, rtt := fa.onFeedback(now, []rtcp.Packet{report})
+	reference := time.Time{}.Add(time.Second)
+	assert.Equal(t, []packetResult{
+		{
+			sequenceNumber: 2, size: 1000, departure: start.Add(2 * time.Millisecond),
+			arrival: reference.Add(-500 * time.Millisecond), received: true,
+		},
+		{
+			sequenceNumber: 3, size: 500, departure: start.Add(3 * time.Millisecond),
+			arrival: reference.Add(-250 * time.Millisecond), received: true,
+		},
+		{sequenceNumber: 4, size: 1000, departure: start.Add(4 * time.Millisecond), received: true},
+	}, results)
+	// The receiver held the latest packet for 250ms before sending the
+	// report, the shortest time from sending to receiving the report was
+	// 996ms.
+	assert.Equal(t, 746*time.Millisecond, rtt)
+	results, _ = fa.onFeedback(now.Add(lossReorderWindow), nil)
+	assert.Equal(t, []packetResult{{sequenceNumber: 5, size: 500, departure: start.Add(5 * time.Millisecond)}}, results)
+}
+
+func TestFeedbackAdapterReorderWindow(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	reference := time.Time{}.Add(twccReferenceTimeUnit)
+	fa := newFeedbackAdapter()
+	for i := range 2 {
+		fa.onPacketSent(1, uint16(i), true, 1200, start, 0, 0) // nolint:gosec
+	}
+	lost := []uint16{rtcp.TypeTCCPacketNotReceived, rtcp.TypeTCCPacketNotReceived}
+	now := start.Add(time.Second)
+	results, _ := fa.onFeedback(now, []rtcp.Packet{twccFeedback(0, 2, lost, nil)})
+	assert.Empty(t, results)
+
+	// The first packet arrives within the reorder window.
+	now = now.Add(lossReorderWindow / 2)
+	received := []uint16{rtcp.TypeTCCPacketReceivedSmallDelta}
+	results, _ = fa.onFeedback(now, []rtcp.Packet{twccFeedback(0, 1, received, []time.Duration{time.Millisecond})})
+	assert.Equal(t, []packetResult{
+		{sequenceNumber: 0, size: 1200, departure: start, arrival: reference.Add(time.Millisecond), received: true},
+	}, results)
+
+	// The second one arrives after its reorder window ended.
+	now = now.Add(lossReorderWindow / 2)
+	results, _ = fa.onFeedback(now, nil)
+	assert.Equal(t, []packetResult{{sequenceNumber: 1, size: 1200, departure: start}}, results)
+	results, _ = fa.onFeedback(now, []rtcp.Packet{twccFeedback(1, 1, received, []time.Duration{time.Millisecond})})
+	assert.Empty(t, results)
+}
+
+func TestFeedbackAdapterEvictsOldPackets(t *testing.T) {
+	fa := newFeedbackAdapter()
+	for i := range maxSentPackets + 1 {
+		fa.onPacketSent(1, uint16(i), true, 1200, time.Time{}, 0, 0) // nolint:gosec
+	}
+	assert.Equal(t, maxSentPackets, fa.history.len())
+	_, ok := fa.lookupTWCC(0)
+	assert.False(t, ok)
+	_, ok = fa.lookupTWCC(1)
+	assert.True(t, ok)
+	// The index entry of the first packet was overwritten by the last one.
+	index := fa.rtpIndices[1]
+	_, ok = fa.lookupRTP(1, index, 0)
+	assert.False(t, ok)
+	packet, ok := fa.lookupRTP(1, index, maxSentPackets)
+	if assert.True(t, ok) {
+		assert.Equal(t, uint64(maxSentPackets), packet.sequenceNumber)
+	}
+	_, ok = fa.lookupRTP(1, index, 1)
+	assert.True(t, ok)
+}
+
+func TestFeedbackAdapterLossRateAfterReordering(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	fa := newFeedbackAdapter()
+	for i := range 10 {
+		departure := start.Add(time.Duration(i) * time.Millisecond)
+		fa.onPacketSent(1, uint16(i), true, 1200, departure, 0, 0) // nolint:gosec
+	}
+	lrc, err := newLossRateController(100_000, 50_000, 1_000_000)
+	assert.NoError(t, err)
+	onResults := func(results []packetResult) {
+		for _, result := range results {
+			if result.received {
+				lrc.onPacketAcked(result.size, result.departure)
+			} else {
+				lrc.onPacketLost(result.size, result.departure)
+			}
+		}
+	}
+
+	// The second half of the packets is reported lost, but arrives shortly
+	// after the first feedback was sent.
+	symbols := make([]uint16, 10)
+	deltas := []time.Duration{}
+	for i := range symbols {
+		symbols[i] = rtcp.TypeTCCPacketNotReceived
+		if i < 5 {
+			symbols[i] = rtcp.TypeTCCPacketReceivedSmallDelta
+			deltas = append(deltas, time.Millisecond)
+		}
+	}
+	now := start.Add(time.Second)
+	results, _ := fa.onFeedback(now, []rtcp.Packet{twccFeedback(0, 10, symbols, deltas)})
+	onResults(results)
+	results, _ = fa.onFeedback(now.Add(lossReorderWindow/2), []rtcp.Packet{twccFeedback(5, 5, []uint16{
+		rtcp.TypeTCCPacketReceivedSmallDelta,
+		rtcp.TypeTCCPacketReceivedSmallDelta,
+		rtcp.TypeTCCPacketReceivedSmallDelta,
+		rtcp.TypeTCCPacketReceivedSmallDelta,
+		rtcp.TypeTCCPacketReceivedSmallDelta,
+	}, deltas)})
+	onResults(results)
+	results, _ = fa.onFeedback(now.Add(lossReorderWindow), nil)
+	onResults(results)
+
+	assert.Equal(t, 10, lrc.packetsSinceLastUpdate)
+	assert.Equal(t, 0, lrc.lostSinceLastUpdate)
+}
+
+func TestFeedbackAdapterReport(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	fa := newFeedbackAdapter()
+	for i := range 3 {
+		fa.onPacketSent(1, uint16(65535+i), false, 1000, start.Add(time.Duration(i)*time.Millisecond), 0, 0) // nolint:gosec
+	}
+	// The rtpfb.Interceptor numbers packets itself and reports unknown
+	// packets, too.
+	arrival := time.Time{}.Add(time.Minute)
+	report := rtpfb.Report{
+		RTT: 50 * time.Millisecond,
+		PacketReports: []rtpfb.PacketReport{
+			{SSRC: 1, SequenceNumber: 10, RTPSequenceNumber: 0, Arrived: false},
+			{SSRC: 1, SequenceNumber: 11, RTPSequenceNumber: 1, Arrived: true, Arrival: arrival},
+			{SSRC: 1, SequenceNumber: 12, RTPSequenceNumber: 65535, Arrived: true, Arrival: arrival.Add(-time.Millisecond)},
+			{SSRC: 2, SequenceNumber: 13, RTPSequenceNumber: 0, Arrived: true, Arrival: arrival},
+		},
+	}
+	now := start.Add(time.Second)
+	results, rtt := fa.onReport(now, report)
+	assert.Equal(t, []packetResult{
+		{sequenceNumber: 0, size: 1000, departure: start, arrival: arrival.Add(-time.Millisecond), received: true},
+		{
+			sequenceNumber: 2, size: 1000, departure: start.Add(2 * time.Millisecond),
+			arrival: arrival, received: true,
+		},
+	}, results)
+	assert.Equal(t, 50*time.Millisecond, rtt)
+
+	results, rtt = fa.onReport(now.Add(lossReorderWindow), rtpfb.Report{RTT: time.Hour})
+	assert.Equal(t, []packetResult{{sequenceNumber: 1, size: 1000, departure: start.Add(time.Millisecond)}}, results)
+	assert.Zero(t, rtt)
+}
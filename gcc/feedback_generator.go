@@ -0,0 +1,389 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+
+	"github.com/pion/rtcp"
+)
+
+const (
+	// twccHeaderSize is the size of a TWCC packet without chunks and deltas.
+	twccHeaderSize = 20
+	// twccDeltaUnit is the resolution of the receive deltas of TWCC.
+	twccDeltaUnit = 250 * time.Microsecond
+	// twccMaxRunLength is the longest run of a run length chunk and
+	// twccVectorSymbols the number of two bit symbols of a status vector
+	// chunk.
+	twccMaxRunLength  = 1<<13 - 1
+	twccVectorSymbols = 7
+
+	// ccfbHeaderSize is the size of a CCFB packet without report blocks,
+	// ccfbBlockHeaderSize the size of a report block without metric blocks
+	// and ccfbMaxMetricBlocks the largest number of metric blocks in a report
+	// block.
+	ccfbHeaderSize      = 12
+	ccfbBlockHeaderSize = 8
+	ccfbMaxMetricBlocks = 1 << 14
+	// ccfbMaxArrivalTimeOffset is the largest arrival time offset in 1/1024
+	// seconds. Larger offsets are reported as the largest.
+	ccfbMaxArrivalTimeOffset = 0x1FFE
+
+	// maxFeedbackRange is the largest number of sequence numbers the
+	// generator keeps unreported. Older sequence numbers are dropped, e.g.
+	// after a long gap in the sequence numbers.
+	maxFeedbackRange = 1 << 14
+
+	// ntpEpochOffset is the number of seconds between the NTP and the Unix
+	// epoch.
+	ntpEpochOffset = 2_208_988_800
+)
+
+// FeedbackFormat is the format of the congestion control feedback sent by the
+// receiver.
+type FeedbackFormat int
+
+const (
+	// FeedbackCCFB is RTCP congestion control feedback as defined in RFC
+	// 8888.
+	FeedbackCCFB FeedbackFormat = iota
+	// FeedbackTWCC is transport wide congestion control feedback as defined
+	// in draft-holmer-rmcat-transport-wide-cc-extensions-01.
+	FeedbackTWCC
+)
+
+// feedbackRange records the arrival times of the packets of one sequence
+// number space since the last feedback.
+type feedbackRange struct {
+	unwrapper *unwrapper
+	arrivals  map[int64]time.Time
+	next      int64
+	highest   int64
+}
+
+func newFeedbackRange() *feedbackRange {
+	return &feedbackRange{
+		unwrapper: newUnwrapper(16),
+		arrivals:  map[int64]time.Time{},
+		next:      0,
+		highest:   0,
+	}
+}
+
+func (r *feedbackRange) onPacket(sequenceNumber uint16, arrival time.Time) {
+	first := !r.unwrapper.initialized
+	unwrapped := r.unwrapper.unwrap(uint64(sequenceNumber))
+	if first {
+		r.next = unwrapped
+		r.highest = unwrapped
+	}
+	if unwrapped < r.next {
+		// Already reported as lost.
+		return
+	}
+	r.arrivals[unwrapped] = arrival
+	r.highest = max(r.highest, unwrapped)
+	for r.highest-r.next >= maxFeedbackRange {
+		delete(r.arrivals, r.next)
+		r.next++
+	}
+}
+
+// pending returns whether there are sequence numbers to report.
+func (r *feedbackRange) pending() bool {
+	return r.unwrapper.initialized && r.next <= r.highest
+}
+
+// advance marks all sequence numbers before next as reported.
+func (r *feedbackRange) advance(next int64) {
+	for ; r.next < next; r.next++ {
+		delete(r.arrivals, r.next)
+	}
+}
+
+// feedbackGenerator records the arrival times of incoming packets and builds
+// TWCC or CCFB packets reporting them. Each packet is reported once, and
+// packets which arrive after their sequence number was reported as lost are
+// ignored. Feedback is split into several packets of at most maxPacketSize
+// bytes.
+type feedbackGenerator struct {
+	format        FeedbackFormat
+	maxPacketSize int
+	senderSSRC    uint32
+
+	// epoch is the arrival time of the first packet. Arrival times are
+	// encoded relative to it.
+	epoch time.Time
+
+	twcc          *feedbackRange
+	twccMediaSSRC uint32
+	fbPktCount    uint8
+
+	ccfb  map[uint32]*feedbackRange
+	ssrcs []uint32
+}
+
+func newFeedbackGenerator(format FeedbackFormat, maxPacketSize int, senderSSRC uint32) *feedbackGenerator {
+	return &feedbackGenerator{
+		format:        format,
+		maxPacketSize: maxPacketSize,
+		senderSSRC:    senderSSRC,
+		epoch:         time.Time{},
+		twcc:          newFeedbackRange(),
+		twccMediaSSRC: 0,
+		fbPktCount:    0,
+		ccfb:          map[uint32]*feedbackRange{},
+		ssrcs:         nil,
+	}
+}
+
+// onPacket records the arrival of a packet. Depending on the format, the
+// packet is identified by its transport wide or its RTP sequence number.
+func (g *feedbackGenerator) onPacket(
+	arrival time.Time,
+	ssrc uint32,
+	sequenceNumber uint16,
+	hasTWCC bool,
+	twccSequenceNumber uint16,
+) {
+	if g.epoch.IsZero() {
+		g.epoch = arrival
+	}
+	switch g.format {
+	case FeedbackTWCC:
+		if !hasTWCC {
+			return
+		}
+		if !g.twcc.unwrapper.initialized {
+			g.twccMediaSSRC = ssrc
+		}
+		g.twcc.onPacket(twccSequenceNumber, arrival)
+	case FeedbackCCFB:
+		r, ok := g.ccfb[ssrc]
+		if !ok {
+			r = newFeedbackRange()
+			g.ccfb[ssrc] = r
+			g.ssrcs = append(g.ssrcs, ssrc)
+		}
+		r.onPacket(sequenceNumber, arrival)
+	}
+}
+
+// removeStream forgets the packets of the stream with ssrc, which are only
+// reported in CCFB.
+func (g *feedbackGenerator) removeStream(ssrc uint32) {
+	delete(g.ccfb, ssrc)
+	for i, s := range g.ssrcs {
+		if s == ssrc {
+			g.ssrcs = append(g.ssrcs[:i], g.ssrcs[i+1:]...)
+
+			break
+		}
+	}
+}
+
+// build returns the feedback for all packets recorded since the last call.
+func (g *feedbackGenerator) build(now time.Time) []rtcp.Packet {
+	switch g.format {
+	case FeedbackTWCC:
+		return g.buildTWCC()
+	case FeedbackCCFB:
+		return g.buildCCFB(now)
+	default:
+		return nil
+	}
+}
+
+func (g *feedbackGenerator) buildTWCC() []rtcp.Packet {
+	var pkts []rtcp.Packet
+	for g.twcc.pending() {
+		pkt, next := g.buildTWCCPacket()
+		pkts = append(pkts, pkt)
+		g.twcc.advance(next)
+	}
+
+	return pkts
+}
+
+// buildTWCCPacket returns a TWCC packet reporting the sequence numbers from
+// the next unreported one, and the sequence number following the last one it
+// reports.
+func (g *feedbackGenerator) buildTWCCPacket() (*rtcp.TransportLayerCC, int64) {
+	// The reference time is taken from the first packet received, so the
+	// first delta is always small.
+	var referenceUnits int64
+	for seq := g.twcc.next; seq <= g.twcc.highest; seq++ {
+		if arrival, ok := g.twcc.arrivals[seq]; ok {
+			referenceUnits = int64(arrival.Sub(g.epoch) / twccReferenceTimeUnit)
+
+			break
+		}
+	}
+	lastUnits := referenceUnits * int64(twccReferenceTimeUnit/twccDeltaUnit)
+
+	symbols := []uint16{}
+	deltas := []*rtcp.RecvDelta{}
+	deltaSize := 0
+	seq := g.twcc.next
+	for ; seq <= g.twcc.highest && len(symbols) < 1<<16-1; seq++ {
+		symbol := uint16(rtcp.TypeTCCPacketNotReceived)
+		var delta *rtcp.RecvDelta
+		size := 0
+		if arrival, ok := g.twcc.arrivals[seq]; ok {
+			units := int64((arrival.Sub(g.epoch) + twccDeltaUnit/2) / twccDeltaUnit)
+			switch d := units - lastUnits; {
+			case d >= 0 && d <= 0xFF:
+				symbol = rtcp.TypeTCCPacketReceivedSmallDelta
+				size = 1
+			case d >= -1<<15 && d < 1<<15:
+				symbol = rtcp.TypeTCCPacketReceivedLargeDelta
+				size = 2
+			default:
+				// The delta does not fit, start a new packet with a new
+				// reference time.
+				return g.twccPacket(referenceUnits, symbols, deltas, deltaSize), seq
+			}
+			delta = &rtcp.RecvDelta{Type: symbol, Delta: (units - lastUnits) * twccDeltaUnit.Microseconds()}
+			lastUnits = units
+		}
+		// Every chunk but the last covers at least twccVectorSymbols
+		// symbols, which bounds the number of chunks.
+		chunks := (len(symbols) + twccVectorSymbols) / twccVectorSymbols
+		if len(symbols) > 0 && (twccHeaderSize+2*chunks+deltaSize+size+3)/4*4 > g.maxPacketSize {
+			break
+		}
+		symbols = append(symbols, symbol)
+		if delta != nil {
+			deltas = append(deltas, delta)
+			deltaSize += size
+		}
+	}
+
+	return g.twccPacket(referenceUnits, symbols, deltas, deltaSize), seq
+}
+
+func (g *feedbackGenerator) twccPacket(
+	referenceUnits int64,
+	symbols []uint16,
+	deltas []*rtcp.RecvDelta,
+	deltaSize int,
+) *rtcp.TransportLayerCC {
+	pkt := &rtcp.TransportLayerCC{
+		Header:             rtcp.Header{},
+		SenderSSRC:         g.senderSSRC,
+		MediaSSRC:          g.twccMediaSSRC,
+		BaseSequenceNumber: uint16(g.twcc.next),                  // nolint:gosec
+		PacketStatusCount:  uint16(len(symbols)),                 // nolint:gosec
+		ReferenceTime:      uint32(referenceUnits) & (1<<24 - 1), // nolint:gosec
+		FbPktCount:         g.fbPktCount,
+		PacketChunks:       twccChunks(symbols),
+		RecvDeltas:         deltas,
+	}
+	g.fbPktCount++
+	size := pkt.MarshalSize()
+	pkt.Header = rtcp.Header{
+		Padding: (twccHeaderSize+2*len(pkt.PacketChunks)+deltaSize)%4 != 0,
+		Count:   rtcp.FormatTCC,
+		Type:    rtcp.TypeTransportSpecificFeedback,
+		Length:  uint16(size/4 - 1), // nolint:gosec
+	}
+
+	return pkt
+}
+
+// twccChunks encodes symbols as run length chunks where at least
+// twccVectorSymbols symbols are equal, and as two bit status vector chunks
+// otherwise.
+func twccChunks(symbols []uint16) []rtcp.PacketStatusChunk {
+	chunks := []rtcp.PacketStatusChunk{}
+	for i := 0; i < len(symbols); {
+		run := 1
+		for i+run < len(symbols) && symbols[i+run] == symbols[i] && run < twccMaxRunLength {
+			run++
+		}
+		if run >= twccVectorSymbols {
+			chunks = append(chunks, &rtcp.RunLengthChunk{
+				Type:               rtcp.TypeTCCRunLengthChunk,
+				PacketStatusSymbol: symbols[i],
+				RunLength:          uint16(run), // nolint:gosec
+			})
+			i += run
+
+			continue
+		}
+		end := min(i+twccVectorSymbols, len(symbols))
+		chunks = append(chunks, &rtcp.StatusVectorChunk{
+			Type:       rtcp.TypeTCCStatusVectorChunk,
+			SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+			SymbolList: append([]uint16(nil), symbols[i:end]...),
+		})
+		i = end
+	}
+
+	return chunks
+}
+
+func (g *feedbackGenerator) buildCCFB(now time.Time) []rtcp.Packet {
+	var pkts []rtcp.Packet
+	report := g.newCCFBReport(now)
+	size := ccfbHeaderSize
+	for _, ssrc := range g.ssrcs {
+		r := g.ccfb[ssrc]
+		for r.pending() {
+			// Metric blocks are two bytes each and padded to a multiple of
+			// four bytes.
+			available := min((g.maxPacketSize-size-ccfbBlockHeaderSize)/4*2, ccfbMaxMetricBlocks)
+			if available <= 0 {
+				pkts = append(pkts, report)
+				report = g.newCCFBReport(now)
+				size = ccfbHeaderSize
+
+				continue
+			}
+			count := min(int(r.highest-r.next+1), available)
+			block := rtcp.CCFeedbackReportBlock{
+				MediaSSRC:     ssrc,
+				BeginSequence: uint16(r.next), // nolint:gosec
+				MetricBlocks:  make([]rtcp.CCFeedbackMetricBlock, count),
+			}
+			for i := range block.MetricBlocks {
+				arrival, ok := r.arrivals[r.next+int64(i)]
+				if !ok {
+					continue
+				}
+				offset := (now.Sub(arrival)*1024 + time.Second/2) / time.Second
+				block.MetricBlocks[i] = rtcp.CCFeedbackMetricBlock{
+					Received:          true,
+					ECN:               rtcp.ECNNonECT,
+					ArrivalTimeOffset: uint16(min(max(offset, 0), ccfbMaxArrivalTimeOffset)), // nolint:gosec
+				}
+			}
+			report.ReportBlocks = append(report.ReportBlocks, block)
+			size += ccfbBlockHeaderSize + (count+1)/2*4
+			r.advance(r.next + int64(count))
+		}
+	}
+	if len(report.ReportBlocks) > 0 {
+		pkts = append(pkts, report)
+	}
+
+	return pkts
+}
+
+func (g *feedbackGenerator) newCCFBReport(now time.Time) *rtcp.CCFeedbackReport {
+	return &rtcp.CCFeedbackReport{
+		SenderSSRC:      g.senderSSRC,
+		ReportBlocks:    nil,
+		ReportTimestamp: ntpShortTime(now),
+	}
+}
+
+// ntpShortTime returns the middle 32 bits of the NTP timestamp of t.
+func ntpShortTime(t time.Time) uint32 {
+	seconds := uint64(t.Unix() + ntpEpochOffset)                   // nolint:gosec
+	fraction := uint64(t.Nanosecond()) << 16 / uint64(time.Second) // nolint:gosec
+
+	return uint32(seconds<<16 | fraction) // nolint:gosec
+}
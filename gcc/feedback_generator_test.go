@@ -0,0 +1,151 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/pion/rtcp"
+	"github.com/stretchr/testify/assert"
+)
+
+// roundTrip marshals and unmarshals pkts like they are sent over the wire.
+func roundTrip(t *testing.T, pkts []rtcp.Packet, maxSize int) []rtcp.Packet {
+	t.Helper()
+
+	var result []rtcp.Packet
+	for _, pkt := range pkts {
+		raw, err := pkt.Marshal()
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, len(raw), maxSize)
+		unmarshaled, err := rtcp.Unmarshal(raw)
+		assert.NoError(t, err)
+		result = append(result, unmarshaled...)
+	}
+
+	return result
+}
+
+func TestFeedbackGenerator(t *testing.T) {
+	for _, format := range []FeedbackFormat{FeedbackCCFB, FeedbackTWCC} {
+		for _, maxSize := range []int{1200, 64} {
+			t.Run(fmt.Sprintf("%v/%v", format, maxSize), func(t *testing.T) {
+				adapter := newFeedbackAdapter()
+				generator := newFeedbackGenerator(format, maxSize, 1)
+				start := time.Unix(1000, 0)
+				arrivals := map[uint64]time.Time{}
+				// Sequence numbers wrap around, every tenth packet is lost
+				// and there is a gap of 100ms in the middle. The packets
+				// sent before without TWCC header extension make the
+				// transport wide sequence numbers wrap around, too.
+				for range 65300 {
+					adapter.onPacketSent(1, 0, false, 1000, start, 0, 0)
+				}
+				for i := 0; i < 500; i++ {
+					seq := uint16(65300 + i) // nolint:gosec
+					departure := start.Add(time.Duration(i) * time.Millisecond)
+					sequenceNumber := adapter.onPacketSent(2, seq, true, 1000, departure, 0, 0)
+					if i%10 == 9 {
+						continue
+					}
+					arrival := departure.Add(20*time.Millisecond + time.Duration(i%7)*time.Millisecond)
+					if i > 250 {
+						arrival = arrival.Add(100 * time.Millisecond)
+					}
+					arrivals[sequenceNumber] = arrival
+					generator.onPacket(arrival, 2, seq, true, uint16(sequenceNumber)) // nolint:gosec
+				}
+				now := start.Add(time.Second)
+				pkts := roundTrip(t, generator.build(now), maxSize)
+				if maxSize < 1200 {
+					assert.Greater(t, len(pkts), 1)
+				}
+				assert.Empty(t, generator.build(now))
+
+				// The last packet is lost, but not reported until a later
+				// packet arrives. The other losses are reported once their
+				// reorder window ended.
+				results, _ := adapter.onFeedback(now, pkts)
+				losses, _ := adapter.onFeedback(now.Add(lossReorderWindow), nil)
+				results = append(results, losses...)
+				assert.Len(t, results, 499)
+				var firstArrival, firstReported time.Time
+				for _, result := range results {
+					arrival, ok := arrivals[result.sequenceNumber]
+					assert.Equal(t, ok, result.received, "sequence number %v", result.sequenceNumber)
+					if !ok {
+						continue
+					}
+					// Arrival times are only relative, compare them to the
+					// first arrival.
+					if firstArrival.IsZero() {
+						firstArrival = arrival
+						firstReported = result.arrival
+					}
+					assert.InDelta(t, arrival.Sub(firstArrival), result.arrival.Sub(firstReported), float64(time.Millisecond))
+				}
+			})
+		}
+	}
+}
+
+func TestFeedbackGeneratorIgnoresLatePackets(t *testing.T) {
+	generator := newFeedbackGenerator(FeedbackCCFB, 1200, 1)
+	now := time.Unix(1000, 0)
+	generator.onPacket(now, 2, 1, false, 0)
+	generator.onPacket(now, 2, 3, false, 0)
+	pkts := generator.build(now)
+	assert.Len(t, pkts, 1)
+	report, ok := pkts[0].(*rtcp.CCFeedbackReport)
+	assert.True(t, ok)
+	assert.Len(t, report.ReportBlocks, 1)
+	assert.Equal(t, uint16(1), report.ReportBlocks[0].BeginSequence)
+	assert.Equal(t, []bool{true, false, true}, []bool{
+		report.ReportBlocks[0].MetricBlocks[0].Received,
+		report.ReportBlocks[0].MetricBlocks[1].Received,
+		report.ReportBlocks[0].MetricBlocks[2].Received,
+	})
+
+	// Sequence number 2 was reported as lost already.
+	generator.onPacket(now, 2, 2, false, 0)
+	assert.Empty(t, generator.build(now))
+}
+
+func TestFeedbackGeneratorTWCCWithoutExtension(t *testing.T) {
+	generator := newFeedbackGenerator(FeedbackTWCC, 1200, 1)
+	now := time.Unix(1000, 0)
+	generator.onPacket(now, 2, 1, false, 0)
+	assert.Empty(t, generator.build(now))
+}
+
+func TestTWCCChunks(t *testing.T) {
+	received := uint16(rtcp.TypeTCCPacketReceivedSmallDelta)
+	lost := uint16(rtcp.TypeTCCPacketNotReceived)
+	symbols := []uint16{received, lost, received}
+	for i := 0; i < 20; i++ {
+		symbols = append(symbols, lost)
+	}
+	symbols = append(symbols, received)
+
+	chunks := twccChunks(symbols)
+	assert.Equal(t, []rtcp.PacketStatusChunk{
+		&rtcp.StatusVectorChunk{
+			Type:       rtcp.TypeTCCStatusVectorChunk,
+			SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+			SymbolList: []uint16{received, lost, received, lost, lost, lost, lost},
+		},
+		&rtcp.RunLengthChunk{
+			Type:               rtcp.TypeTCCRunLengthChunk,
+			PacketStatusSymbol: lost,
+			RunLength:          16,
+		},
+		&rtcp.StatusVectorChunk{
+			Type:       rtcp.TypeTCCStatusVectorChunk,
+			SymbolSize: rtcp.TypeTCCSymbolSizeTwoBit,
+			SymbolList: []uint16{received},
+		},
+	}, chunks)
+}
@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtp"
+)
+
+const (
+	defaultFeedbackInterval       = 50 * time.Millisecond
+	defaultFeedbackBandwidthShare = 0.05
+	defaultFeedbackMaxPacketSize  = 1200
+
+	// maxFeedbackInterval is the longest interval to which the interval is
+	// stretched to keep the feedback within its share of the bandwidth.
+	maxFeedbackInterval = 250 * time.Millisecond
+
+	// minFeedbackPacketSize is the smallest maximum packet size, which leaves
+	// room for a TWCC packet with a few chunks and deltas.
+	minFeedbackPacketSize = 64
+)
+
+var errInvalidFeedbackOption = errors.New("invalid feedback option")
+
+// FeedbackInterceptorOption can be used to set initial options on feedback
+// interceptors.
+type FeedbackInterceptorOption func(*FeedbackInterceptorFactory) error
+
+// FeedbackLoggerFactory sets the logger factory used by the interceptor.
+func FeedbackLoggerFactory(lf logging.LoggerFactory) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		f.loggerFactory = lf
+
+		return nil
+	}
+}
+
+// FeedbackReportFormat sets the format of the feedback. The default is RFC
+// 8888 congestion control feedback. TWCC feedback requires the sender to add
+// the transport wide sequence number header extension.
+func FeedbackReportFormat(format FeedbackFormat) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		if format != FeedbackCCFB && format != FeedbackTWCC {
+			return fmt.Errorf("%w: unknown format %v", errInvalidFeedbackOption, format)
+		}
+		f.format = format
+
+		return nil
+	}
+}
+
+// FeedbackInterval sets the shortest interval between two feedback reports.
+// The default is 50ms.
+func FeedbackInterval(interval time.Duration) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		if interval <= 0 {
+			return fmt.Errorf("%w: interval %v must be positive", errInvalidFeedbackOption, interval)
+		}
+		f.interval = interval
+
+		return nil
+	}
+}
+
+// FeedbackBandwidthShare sets the share of the received bitrate the feedback
+// may use. If the feedback would use more, the interval is lengthened up to
+// 250ms. The default is 0.05 as recommended by RFC 8888. A share of 0 keeps
+// the interval fixed.
+func FeedbackBandwidthShare(share float64) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		if share < 0 || share > 1 {
+			return fmt.Errorf("%w: bandwidth share %v must be in [0, 1]", errInvalidFeedbackOption, share)
+		}
+		f.bandwidthShare = share
+
+		return nil
+	}
+}
+
+// FeedbackMaxPacketSize sets the largest size of a feedback packet in bytes.
+// Feedback for more packets is split into several feedback packets. The
+// default is 1200 bytes.
+func FeedbackMaxPacketSize(size int) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		if size < minFeedbackPacketSize {
+			return fmt.Errorf(
+				"%w: max packet size %d must be at least %d",
+				errInvalidFeedbackOption, size, minFeedbackPacketSize,
+			)
+		}
+		f.maxPacketSize = size
+
+		return nil
+	}
+}
+
+func feedbackTimeFactory(timestamp func() time.Time) FeedbackInterceptorOption {
+	return func(f *FeedbackInterceptorFactory) error {
+		f.timestamp = timestamp
+
+		return nil
+	}
+}
+
+// FeedbackInterceptorFactory is a factory for feedback interceptors.
+type FeedbackInterceptorFactory struct {
+	loggerFactory  logging.LoggerFactory
+	format         FeedbackFormat
+	interval       time.Duration
+	bandwidthShare float64
+	maxPacketSize  int
+	timestamp      func() time.Time
+}
+
+// NewFeedbackInterceptor returns a new feedback InterceptorFactory.
+// Interceptors created by the factory record the arrival times of the packets
+// received on all remote streams and report them to the sender in TWCC or RFC
+// 8888 feedback, which the sender needs to run GCC.
+func NewFeedbackInterceptor(opts ...FeedbackInterceptorOption) (*FeedbackInterceptorFactory, error) {
+	factory := &FeedbackInterceptorFactory{
+		loggerFactory:  logging.NewDefaultLoggerFactory(),
+		format:         FeedbackCCFB,
+		interval:       defaultFeedbackInterval,
+		bandwidthShare: defaultFeedbackBandwidthShare,
+		maxPacketSize:  defaultFeedbackMaxPacketSize,
+		timestamp:      time.Now,
+	}
+	for _, opt := range opts {
+		if err := opt(factory); err != nil {
+			return nil, err
+		}
+	}
+
+	return factory, nil
+}
+
+// NewInterceptor returns a new interceptor sending feedback for all remote
+// streams.
+func (f *FeedbackInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	return f.newFeedbackInterceptor(), nil
+}
+
+func (f *FeedbackInterceptorFactory) newFeedbackInterceptor() *FeedbackInterceptor {
+	feedbackInterceptor := &FeedbackInterceptor{
+		NoOp:           interceptor.NoOp{},
+		log:            f.loggerFactory.NewLogger("gcc_feedback_interceptor"),
+		timestamp:      f.timestamp,
+		interval:       f.interval,
+		bandwidthShare: f.bandwidthShare,
+		lock:           sync.Mutex{},
+		generator:      newFeedbackGenerator(f.format, f.maxPacketSize, rand.Uint32()), // nolint:gosec
+		receivedBytes:  0,
+		lastFeedback:   time.Time{},
+		writer:         nil,
+		wg:             sync.WaitGroup{},
+		close:          make(chan struct{}),
+	}
+	feedbackInterceptor.wg.Add(1)
+	go feedbackInterceptor.run()
+
+	return feedbackInterceptor
+}
+
+// FeedbackInterceptor records the arrival times of the packets received on
+// all remote streams and periodically sends TWCC or RFC 8888 feedback
+// reporting them.
+type FeedbackInterceptor struct {
+	interceptor.NoOp
+	log            logging.LeveledLogger
+	timestamp      func() time.Time
+	interval       time.Duration
+	bandwidthShare float64
+
+	lock          sync.Mutex
+	generator     *feedbackGenerator
+	receivedBytes int
+	lastFeedback  time.Time
+	writer        interceptor.RTCPWriter
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// BindRTCPWriter implements interceptor.Interceptor.
+func (i *FeedbackInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.writer = writer
+
+	return writer
+}
+
+// BindRemoteStream implements interceptor.Interceptor.
+func (i *FeedbackInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	var twccHdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == transportCCURI {
+			twccHdrExtID = uint8(e.ID) // nolint:gosec
+
+			break
+		}
+	}
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+		// The arrival time is taken before parsing, so it is as close to the
+		// actual arrival as possible.
+		arrival := i.timestamp()
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return n, attr, err
+		}
+		var twcc rtp.TransportCCExtension
+		hasTWCC := twccHdrExtID != 0 && twcc.Unmarshal(header.GetExtension(twccHdrExtID)) == nil
+
+		i.lock.Lock()
+		i.generator.onPacket(arrival, header.SSRC, header.SequenceNumber, hasTWCC, twcc.TransportSequence)
+		i.receivedBytes += n
+		i.lock.Unlock()
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream implements interceptor.Interceptor.
+func (i *FeedbackInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.generator.removeStream(info.SSRC)
+}
+
+func (i *FeedbackInterceptor) run() {
+	defer i.wg.Done()
+	timer := time.NewTimer(i.interval)
+	defer timer.Stop()
+	for {
+		select {
+		case <-i.close:
+			return
+		case <-timer.C:
+			timer.Reset(i.sendFeedback())
+		}
+	}
+}
+
+// sendFeedback sends feedback for the packets received since the last
+// feedback and returns the time until the next feedback.
+func (i *FeedbackInterceptor) sendFeedback() time.Duration {
+	now := i.timestamp()
+
+	i.lock.Lock()
+	writer := i.writer
+	if writer == nil {
+		// The arrivals are kept until they can be reported.
+		i.lock.Unlock()
+
+		return i.interval
+	}
+	pkts := i.generator.build(now)
+	size := 0
+	for _, pkt := range pkts {
+		size += pkt.MarshalSize()
+	}
+	interval := i.nextInterval(now, size)
+	i.receivedBytes = 0
+	i.lastFeedback = now
+	i.lock.Unlock()
+
+	if len(pkts) == 0 {
+		return interval
+	}
+	if _, err := writer.Write(pkts, interceptor.Attributes{}); err != nil {
+		i.log.Warnf("failed to write feedback: %v", err)
+	}
+
+	return interval
+}
+
+// nextInterval returns the interval after which feedback of size bytes uses
+// the configured share of the bitrate received since the last feedback.
+func (i *FeedbackInterceptor) nextInterval(now time.Time, size int) time.Duration {
+	if i.bandwidthShare == 0 || i.lastFeedback.IsZero() || i.receivedBytes == 0 {
+		return i.interval
+	}
+	elapsed := now.Sub(i.lastFeedback)
+	if elapsed <= 0 {
+		return i.interval
+	}
+	receivedRate := float64(8*i.receivedBytes) / elapsed.Seconds()
+	interval := time.Duration(float64(8*size) / (i.bandwidthShare * receivedRate) * float64(time.Second))
+
+	return max(i.interval, min(interval, max(i.interval, maxFeedbackInterval)))
+}
+
+// Close stops sending feedback.
+func (i *FeedbackInterceptor) Close() error {
+	select {
+	case <-i.close:
+	default:
+		close(i.close)
+	}
+	i.wg.Wait()
+
+	return nil
+}
@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewFeedbackInterceptor(t *testing.T) {
+	cases := []struct {
+		name string
+		opts []FeedbackInterceptorOption
+		err  error
+	}{
+		{name: "defaults", opts: nil, err: nil},
+		{
+			name: "valid",
+			opts: []FeedbackInterceptorOption{
+				FeedbackReportFormat(FeedbackTWCC),
+				FeedbackInterval(100 * time.Millisecond),
+				FeedbackBandwidthShare(0),
+				FeedbackMaxPacketSize(500),
+			},
+			err: nil,
+		},
+		{name: "unknownFormat", opts: []FeedbackInterceptorOption{FeedbackReportFormat(7)}, err: errInvalidFeedbackOption},
+		{name: "zeroInterval", opts: []FeedbackInterceptorOption{FeedbackInterval(0)}, err: errInvalidFeedbackOption},
+		{
+			name: "negativeShare",
+			opts: []FeedbackInterceptorOption{FeedbackBandwidthShare(-0.1)},
+			err:  errInvalidFeedbackOption,
+		},
+		{
+			name: "smallPacketSize",
+			opts: []FeedbackInterceptorOption{FeedbackMaxPacketSize(32)},
+			err:  errInvalidFeedbackOption,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := NewFeedbackInterceptor(tc.opts...)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				assert.Nil(t, f)
+
+				return
+			}
+			assert.NoError(t, err)
+			i, err := f.NewInterceptor("")
+			assert.NoError(t, err)
+			assert.NoError(t, i.Close())
+		})
+	}
+}
+
+// rtcpRecorder records the RTCP packets written by an interceptor.
+type rtcpRecorder struct {
+	lock sync.Mutex
+	pkts []rtcp.Packet
+}
+
+func (r *rtcpRecorder) Write(pkts []rtcp.Packet, _ interceptor.Attributes) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.pkts = append(r.pkts, pkts...)
+
+	return 0, nil
+}
+
+func (r *rtcpRecorder) get() []rtcp.Packet {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]rtcp.Packet(nil), r.pkts...)
+}
+
+func TestFeedbackInterceptor(t *testing.T) {
+	var lock sync.Mutex
+	now := time.Unix(1000, 0)
+	f, err := NewFeedbackInterceptor(
+		FeedbackInterval(time.Hour),
+		feedbackTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	i := f.newFeedbackInterceptor()
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+	recorder := &rtcpRecorder{}
+
+	var packet []byte
+	reader := i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPReaderFunc(
+		func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+			return copy(b, packet), nil, nil
+		},
+	))
+	for seq := uint16(0); seq < 10; seq++ {
+		packet, err = (&rtp.Packet{
+			Header:  rtp.Header{Version: 2, SSRC: 1, SequenceNumber: seq},
+			Payload: make([]byte, 1000),
+		}).Marshal()
+		assert.NoError(t, err)
+		lock.Lock()
+		now = now.Add(10 * time.Millisecond)
+		lock.Unlock()
+		_, _, err = reader.Read(make([]byte, 1500), nil)
+		assert.NoError(t, err)
+	}
+
+	// The packets are kept for the first feedback after the RTCP writer is
+	// bound.
+	assert.Equal(t, time.Hour, i.sendFeedback())
+	i.BindRTCPWriter(recorder)
+	assert.Equal(t, time.Hour, i.sendFeedback())
+	pkts := recorder.get()
+	assert.Len(t, pkts, 1)
+	report, ok := pkts[0].(*rtcp.CCFeedbackReport)
+	assert.True(t, ok)
+	assert.Len(t, report.ReportBlocks, 1)
+	assert.Len(t, report.ReportBlocks[0].MetricBlocks, 10)
+	// The last packet arrived when the feedback was sent, the first 90ms
+	// before.
+	assert.Equal(t, uint16(0), report.ReportBlocks[0].MetricBlocks[9].ArrivalTimeOffset)
+	assert.Equal(t, uint16(92), report.ReportBlocks[0].MetricBlocks[0].ArrivalTimeOffset)
+
+	// Without new packets, no feedback is sent.
+	i.sendFeedback()
+	assert.Len(t, recorder.get(), 1)
+}
+
+func TestFeedbackInterceptorInterval(t *testing.T) {
+	f, err := NewFeedbackInterceptor(FeedbackInterval(50 * time.Millisecond))
+	assert.NoError(t, err)
+	i := f.newFeedbackInterceptor()
+	assert.NoError(t, i.Close())
+
+	now := time.Unix(1000, 0)
+	// Without a previous feedback, the rate is unknown.
+	assert.Equal(t, 50*time.Millisecond, i.nextInterval(now, 100))
+
+	i.lastFeedback = now.Add(-100 * time.Millisecond)
+	// 100 kbps, of which 5% allow 100 bytes every 160ms.
+	i.receivedBytes = 1250
+	assert.Equal(t, 160*time.Millisecond, i.nextInterval(now, 100))
+	// 10 kbps would need 1.6s, which is longer than the longest interval.
+	i.receivedBytes = 125
+	assert.Equal(t, maxFeedbackInterval, i.nextInterval(now, 100))
+	// 1 Mbps allows feedback more often than the shortest interval.
+	i.receivedBytes = 12500
+	assert.Equal(t, 50*time.Millisecond, i.nextInterval(now, 100))
+
+	i.bandwidthShare = 0
+	i.receivedBytes = 125
+	assert.Equal(t, 50*time.Millisecond, i.nextInterval(now, 100))
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// arrivalTimeOffsetThreshold is the difference between the inter-arrival
+	// and the inter-departure time above which the arrival clock is assumed
+	// to have jumped.
+	arrivalTimeOffsetThreshold = 3 * time.Second
+
+	// reorderedResetThreshold is the number of consecutive groups arriving
+	// before their predecessor after which the arrival clock is assumed to
+	// have jumped back.
+	reorderedResetThreshold = 3
+)
+
+// ArrivalTimeResetReason is the reason why the arrival times of the remote
+// peer were found to be discontinuous.
+type ArrivalTimeResetReason int
+
+const (
+	// ArrivalTimeJumpedForward means a group arrived much later than its
+	// departure allows.
+	ArrivalTimeJumpedForward ArrivalTimeResetReason = iota
+	// ArrivalTimeJumpedBack means a group arrived much earlier than its
+	// departure allows.
+	ArrivalTimeJumpedBack
+	// ArrivalTimeReordered means several consecutive groups arrived before
+	// the groups which departed before them.
+	ArrivalTimeReordered
+)
+
+func (r ArrivalTimeResetReason) String() string {
+	switch r {
+	case ArrivalTimeJumpedForward:
+		return "arrival time jumped forward"
+	case ArrivalTimeJumpedBack:
+		return "arrival time jumped back"
+	case ArrivalTimeReordered:
+		return "arrival time reordered"
+	default:
+		return fmt.Sprintf("invalid arrival time reset reason: %d", r)
+	}
+}
+
+// interGroupDelta is the difference between two consecutive groups.
+type interGroupDelta struct {
+	// departure is the time between the departures of the last packets.
+	departure time.Duration
+	// arrival is the time between the arrivals of the last packets.
+	arrival time.Duration
+	// size is the difference of the sizes of the groups in bytes.
+	size int
+	// lastArrival is the arrival of the last packet of the newer group.
+	lastArrival time.Time
+}
+
+// interArrival computes the deltas between consecutive groups of packets. It
+// discards groups which departed before the previous group, and starts over
+// if the arrival clock of the remote peer jumped.
+type interArrival struct {
+	onReset func(reason ArrivalTimeResetReason)
+
+	hasPrevious          bool
+	previousDeparture    time.Time
+	previousArrival      time.Time
+	previousSize         int
+	consecutiveReordered int
+}
+
+// newInterArrival returns a new interArrival which calls onReset whenever it
+// starts over.
+func newInterArrival(onReset func(reason ArrivalTimeResetReason)) *interArrival {
+	return &interArrival{
+		onReset:              onReset,
+		hasPrevious:          false,
+		previousDeparture:    time.Time{},
+		previousArrival:      time.Time{},
+		previousSize:         0,
+		consecutiveReordered: 0,
+	}
+}
+
+// onGroup adds the next completed group and returns its delta to the previous
+// group. It returns false if there is no previous group or the group was
+// discarded.
+func (ia *interArrival) onGroup(group arrivalGroup) (interGroupDelta, bool) {
+	if len(group) == 0 {
+		return interGroupDelta{}, false
+	}
+	last := group[len(group)-1]
+	size := 0
+	for _, item := range group {
+		size += item.Size
+	}
+	if !ia.hasPrevious {
+		ia.setPrevious(last, size)
+
+		return interGroupDelta{}, false
+	}
+	departureDelta := last.Departure.Sub(ia.previousDeparture)
+	if departureDelta < 0 {
+		// Reordered relative to the previous group, which would yield a
+		// meaningless delta.
+		return interGroupDelta{}, false
+	}
+	arrivalDelta := last.Arrival.Sub(ia.previousArrival)
+	offset := arrivalDelta - departureDelta
+	if offset >= arrivalTimeOffsetThreshold {
+		ia.reset(ArrivalTimeJumpedForward)
+		ia.setPrevious(last, size)
+
+		return interGroupDelta{}, false
+	}
+	if offset <= -arrivalTimeOffsetThreshold {
+		ia.reset(ArrivalTimeJumpedBack)
+		ia.setPrevious(last, size)
+
+		return interGroupDelta{}, false
+	}
+	if arrivalDelta < 0 {
+		ia.consecutiveReordered++
+		if ia.consecutiveReordered >= reorderedResetThreshold {
+			// The arrival clock jumped back by less than the threshold.
+			ia.reset(ArrivalTimeReordered)
+		}
+		ia.setPrevious(last, size)
+
+		return interGroupDelta{}, false
+	}
+	ia.consecutiveReordered = 0
+	delta := interGroupDelta{
+		departure:   departureDelta,
+		arrival:     arrivalDelta,
+		size:        size - ia.previousSize,
+		lastArrival: last.Arrival,
+	}
+	ia.setPrevious(last, size)
+
+	return delta, true
+}
+
+func (ia *interArrival) setPrevious(last arrivalGroupItem, size int) {
+	ia.hasPrevious = true
+	ia.previousDeparture = last.Departure
+	ia.previousArrival = last.Arrival
+	ia.previousSize = size
+}
+
+func (ia *interArrival) reset(reason ArrivalTimeResetReason) {
+	ia.hasPrevious = false
+	ia.previousDeparture = time.Time{}
+	ia.previousArrival = time.Time{}
+	ia.previousSize = 0
+	ia.consecutiveReordered = 0
+	if ia.onReset != nil {
+		ia.onReset(reason)
+	}
+}
@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInterArrival(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	// group returns a group of two packets of size bytes each, departing at
+	// departure and arriving at arrival, both in milliseconds after start.
+	group := func(departure, arrival, size int) arrivalGroup {
+		d := start.Add(time.Duration(departure) * time.Millisecond)
+		a := start.Add(time.Duration(arrival) * time.Millisecond)
+
+		return arrivalGroup{
+			{SequenceNumber: 0, Departure: d.Add(-time.Millisecond), Arrival: a.Add(-time.Millisecond), Size: size},
+			{SequenceNumber: 1, Departure: d, Arrival: a, Size: size},
+		}
+	}
+	type step struct {
+		group    arrivalGroup
+		expected interGroupDelta
+		ok       bool
+	}
+	cases := []struct {
+		name   string
+		steps  []step
+		resets []ArrivalTimeResetReason
+	}{
+		{
+			name:   "empty",
+			steps:  []step{{group: arrivalGroup{}, expected: interGroupDelta{}, ok: false}},
+			resets: nil,
+		},
+		{
+			name: "deltas",
+			steps: []step{
+				{group: group(10, 30, 100), expected: interGroupDelta{}, ok: false},
+				{
+					group: group(20, 45, 150),
+					expected: interGroupDelta{
+						departure:   10 * time.Millisecond,
+						arrival:     15 * time.Millisecond,
+						size:        100,
+						lastArrival: start.Add(45 * time.Millisecond),
+					},
+					ok: true,
+				},
+				{
+					group: group(30, 50, 100),
+					expected: interGroupDelta{
+						departure:   10 * time.Millisecond,
+						arrival:     5 * time.Millisecond,
+						size:        -100,
+						lastArrival: start.Add(50 * time.Millisecond),
+					},
+					ok: true,
+				},
+			},
+			resets: nil,
+		},
+		{
+			name: "discardsReorderedGroup",
+			steps: []step{
+				{group: group(10, 30, 100), expected: interGroupDelta{}, ok: false},
+				{group: group(5, 40, 100), expected: interGroupDelta{}, ok: false},
+				{
+					group: group(20, 40, 100),
+					expected: interGroupDelta{
+						departure:   10 * time.Millisecond,
+						arrival:     10 * time.Millisecond,
+						size:        0,
+						lastArrival: start.Add(40 * time.Millisecond),
+					},
+					ok: true,
+				},
+			},
+			resets: nil,
+		},
+		{
+			name: "arrivalClockJumpsForward",
+			steps: []step{
+				{group: group(10, 30, 100), expected: interGroupDelta{}, ok: false},
+				{group: group(20, 5000, 100), expected: interGroupDelta{}, ok: false},
+				{
+					group: group(30, 5010, 100),
+					expected: interGroupDelta{
+						departure:   10 * time.Millisecond,
+						arrival:     10 * time.Millisecond,
+						size:        0,
+						lastArrival: start.Add(5010 * time.Millisecond),
+					},
+					ok: true,
+				},
+			},
+			resets: []ArrivalTimeResetReason{ArrivalTimeJumpedForward},
+		},
+		{
+			name: "arrivalClockJumpsBack",
+			steps: []step{
+				{group: group(10, 5000, 100), expected: interGroupDelta{}, ok: false},
+				{group: group(20, 30, 100), expected: interGroupDelta{}, ok: false},
+				{
+					group: group(30, 40, 100),
+					expected: interGroupDelta{
+						departure:   10 * time.Millisecond,
+						arrival:     10 * time.Millisecond,
+						size:        0,
+						lastArrival: start.Add(40 * time.Millisecond),
+					},
+					ok: true,
+				},
+			},
+			resets: []ArrivalTimeResetReason{ArrivalTimeJumpedBack},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var resets []ArrivalTimeResetReason
+			ia := newInterArrival(func(reason ArrivalTimeResetReason) {
+				resets = append(resets, reason)
+			})
+			for i, s := range tc.steps {
+				delta, ok := ia.onGroup(s.group)
+				assert.Equal(t, s.ok, ok, "step %v", i)
+				assert.Equal(t, s.expected, delta, "step %v", i)
+			}
+			assert.Equal(t, tc.resets, resets)
+		})
+	}
+}
+
+func TestInterArrivalResetsAfterConsecutiveReordering(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	var resets []ArrivalTimeResetReason
+	ia := newInterArrival(func(reason ArrivalTimeResetReason) {
+		resets = append(resets, reason)
+	})
+	onGroup := func(departure, arrival time.Duration) bool {
+		_, ok := ia.onGroup(arrivalGroup{{
+			SequenceNumber: 0,
+			Departure:      start.Add(departure),
+			Arrival:        start.Add(arrival),
+			Size:           100,
+		}})
+
+		return ok
+	}
+	assert.False(t, onGroup(0, 100*time.Millisecond))
+	for i := 1; i < reorderedResetThreshold; i++ {
+		assert.False(t, onGroup(time.Duration(i)*10*time.Millisecond, 100*time.Millisecond-time.Duration(i)))
+		assert.Equal(t, i, ia.consecutiveReordered)
+	}
+	assert.Empty(t, resets)
+	assert.False(t, onGroup(50*time.Millisecond, 0))
+	assert.Zero(t, ia.consecutiveReordered)
+	assert.Equal(t, []ArrivalTimeResetReason{ArrivalTimeReordered}, resets)
+	assert.True(t, onGroup(60*time.Millisecond, 10*time.Millisecond))
+}
@@ -0,0 +1,553 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+const transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+var errInvalidPacerOption = errors.New("invalid pacer option")
+
+// InterceptorOption can be used to set initial options on GCC interceptors.
+type InterceptorOption func(*InterceptorFactory) error
+
+// WithLoggerFactory sets the logger factory used by the interceptor.
+func WithLoggerFactory(lf logging.LoggerFactory) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		f.loggerFactory = lf
+
+		return nil
+	}
+}
+
+// WithControllerOptions sets the options used to create the Controller of
+// each interceptor.
+func WithControllerOptions(opts ...Option) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		f.controllerOptions = append(f.controllerOptions, opts...)
+
+		return nil
+	}
+}
+
+// OnTargetBitrateChange sets a callback which is called with the new target
+// bitrate in bits per second whenever the estimate changes. The callback is
+// called from the goroutine reading RTCP and must not block.
+func OnTargetBitrateChange(handler func(bitrate int)) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		f.onTargetBitrateChange = handler
+
+		return nil
+	}
+}
+
+// OnRoundTripTime sets a callback which is called with the round trip time
+// measured on every feedback report acknowledging a packet. The callback is
+// called from the goroutine reading RTCP and must not block.
+func OnRoundTripTime(handler func(rtt time.Duration)) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		f.onRoundTripTime = handler
+
+		return nil
+	}
+}
+
+// WithPacing enables pacing. Outgoing packets are queued and released at
+// factor times the target bitrate. Audio and packets written on the RTX SSRC
+// or with the RTX payload type of a stream are released first. A factor above
+// 1 leaves room for the bursts produced by encoders. Packets are sent from a
+// separate goroutine, so writes of paced packets never return errors.
+//
+// The pacer also sends the probes requested by the Controller. Probes are
+// filled up with padding on the RTX stream of a video stream, so probing
+// requires RTX to be negotiated. The RTP sequence numbers of media packets are
+// never changed, only those of retransmissions are shifted to make room for
+// the padding. The interceptor should be added before a NACK responder, so the
+// responder's retransmissions are written through it and are paced as well.
+func WithPacing(factor float64) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		if factor <= 0 {
+			return fmt.Errorf("%w: pacing factor %v must be positive", errInvalidPacerOption, factor)
+		}
+		f.pacingFactor = factor
+
+		return nil
+	}
+}
+
+// WithMaxPacerQueueDelay sets the longest time packets should wait in the
+// pacer. If packets would wait longer at the pacing rate, the pacer sends
+// faster than the pacing rate. It has no effect unless pacing is enabled with
+// WithPacing.
+func WithMaxPacerQueueDelay(delay time.Duration) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		if delay <= 0 {
+			return fmt.Errorf("%w: max queue delay %v must be positive", errInvalidPacerOption, delay)
+		}
+		f.maxPacerQueueDelay = delay
+
+		return nil
+	}
+}
+
+func interceptorTimeFactory(timestamp func() time.Time) InterceptorOption {
+	return func(f *InterceptorFactory) error {
+		f.timestamp = timestamp
+
+		return nil
+	}
+}
+
+// InterceptorFactory is a factory for GCC interceptors.
+type InterceptorFactory struct {
+	loggerFactory         logging.LoggerFactory
+	controllerOptions     []Option
+	onTargetBitrateChange func(int)
+	onRoundTripTime       func(time.Duration)
+	pacingFactor          float64
+	maxPacerQueueDelay    time.Duration
+	timestamp             func() time.Time
+}
+
+// NewInterceptor returns a new GCC InterceptorFactory. Interceptors created by
+// the factory keep track of outgoing packets and read incoming TWCC and RFC
+// 8888 feedback themselves, so no further interceptors need to be registered on
+// the sender. If the TWCC header extension was negotiated for a stream, they
+// set the transport wide sequence numbers of its packets themselves.
+//
+// If an rtpfb.Interceptor is registered before the GCC interceptor, the
+// rtpfb.Report it adds to the attributes of incoming RTCP packets is used
+// instead of the feedback in the packets. Its packet reports are matched to
+// the packets sent through the GCC interceptor by SSRC and RTP sequence
+// number.
+func NewInterceptor(opts ...InterceptorOption) (*InterceptorFactory, error) {
+	factory := &InterceptorFactory{
+		loggerFactory:         logging.NewDefaultLoggerFactory(),
+		controllerOptions:     []Option{},
+		onTargetBitrateChange: nil,
+		onRoundTripTime:       nil,
+		pacingFactor:          0,
+		maxPacerQueueDelay:    defaultMaxPacerQueueDelay,
+		timestamp:             time.Now,
+	}
+	for _, opt := range opts {
+		if err := opt(factory); err != nil {
+			return nil, err
+		}
+	}
+	// Fail early on invalid controller options instead of on the first
+	// PeerConnection.
+	if _, err := NewController(factory.controllerOptions...); err != nil {
+		return nil, err
+	}
+
+	return factory, nil
+}
+
+// NewInterceptor returns a new interceptor running GCC on the feedback
+// received for all local streams.
+func (f *InterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	return f.newGCCInterceptor()
+}
+
+func (f *InterceptorFactory) newGCCInterceptor() (*Interceptor, error) {
+	controller, err := NewController(f.controllerOptions...)
+	if err != nil {
+		return nil, err
+	}
+
+	var p *pacer
+	if f.pacingFactor > 0 {
+		p = newPacer(f.pacingFactor, f.maxPacerQueueDelay, controller.TargetBitrate())
+	}
+
+	gccInterceptor := &Interceptor{
+		NoOp:                  interceptor.NoOp{},
+		log:                   f.loggerFactory.NewLogger("gcc_interceptor"),
+		timestamp:             f.timestamp,
+		onTargetBitrateChange: f.onTargetBitrateChange,
+		onRoundTripTime:       f.onRoundTripTime,
+		lock:                  sync.Mutex{},
+		feedbackAdapter:       newFeedbackAdapter(),
+		controller:            controller,
+		pacer:                 p,
+		sendLock:              sync.Mutex{},
+		sendBuffer:            nil,
+		streams:               map[uint32]*pacedStream{},
+		paddingStream:         nil,
+		paddingPayload:        nil,
+		wg:                    sync.WaitGroup{},
+		close:                 make(chan struct{}),
+	}
+	if p != nil {
+		gccInterceptor.wg.Add(1)
+		go gccInterceptor.runPacer()
+	}
+
+	return gccInterceptor, nil
+}
+
+// Interceptor runs a Controller on the TWCC or RFC 8888 feedback received for
+// the packets sent on all local streams and, if enabled, paces the outgoing
+// packets according to the target bitrate.
+type Interceptor struct {
+	interceptor.NoOp
+	log                   logging.LeveledLogger
+	timestamp             func() time.Time
+	onTargetBitrateChange func(int)
+	onRoundTripTime       func(time.Duration)
+
+	lock            sync.Mutex
+	feedbackAdapter *feedbackAdapter
+	controller      *Controller
+	pacer           *pacer
+
+	// sendLock serializes releasing packets from the pacer, so packets are
+	// written in the order they were dequeued. The fields below are only used
+	// while holding sendLock.
+	sendLock       sync.Mutex
+	sendBuffer     []pacedPacket
+	streams        map[uint32]*pacedStream
+	paddingStream  *pacedStream
+	paddingPayload []byte
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// TargetBitrate returns the current target bitrate in bits per second.
+func (i *Interceptor) TargetBitrate() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.controller.TargetBitrate()
+}
+
+// BindLocalStream implements interceptor.Interceptor.
+func (i *Interceptor) BindLocalStream(
+	info *interceptor.StreamInfo,
+	writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	var twccHdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		if e.URI == transportCCURI {
+			twccHdrExtID = uint8(e.ID) // nolint:gosec
+
+			break
+		}
+	}
+	audio := strings.HasPrefix(strings.ToLower(info.MimeType), "audio/")
+	if i.pacer != nil && !audio && info.SSRCRetransmission != 0 && info.PayloadTypeRetransmission != 0 {
+		stream := newPacedStream(info, twccHdrExtID, writer)
+		i.sendLock.Lock()
+		i.streams[info.SSRC] = stream
+		i.streams[info.SSRCRetransmission] = stream
+		i.sendLock.Unlock()
+	}
+
+	return interceptor.RTPWriterFunc(func(
+		header *rtp.Header,
+		payload []byte,
+		attributes interceptor.Attributes,
+	) (int, error) {
+		flags := packetFlags(info, header, payload)
+		if i.pacer == nil {
+			i.lock.Lock()
+			i.onPacketSent(header, twccHdrExtID, len(payload), i.timestamp(), flags, 0)
+			i.lock.Unlock()
+
+			return writer.Write(header, payload, attributes)
+		}
+
+		priority := pacerPriorityNormal
+		if audio || flags&sentPacketRetransmission != 0 {
+			priority = pacerPriorityHigh
+		}
+		// The caller may reuse header and payload once Write returns.
+		packet := pacedPacket{
+			header:         header.Clone(),
+			payload:        append([]byte(nil), payload...),
+			attributes:     attributes,
+			writer:         writer,
+			twccHdrExtID:   twccHdrExtID,
+			flags:          flags,
+			probeClusterID: 0,
+			size:           header.MarshalSize() + len(payload),
+			enqueued:       i.timestamp(),
+		}
+		i.lock.Lock()
+		i.pacer.enqueue(packet, priority)
+		i.lock.Unlock()
+		i.releasePackets()
+
+		return packet.size, nil
+	})
+}
+
+// onPacketSent adds the packet with header to the history. If twccHdrExtID is
+// not 0, the transport wide sequence number assigned to the packet is set in
+// its TWCC header extension, so the sequence numbers follow the order in which
+// packets are sent, including padding and retransmissions.
+func (i *Interceptor) onPacketSent(
+	header *rtp.Header,
+	twccHdrExtID uint8,
+	payloadSize int,
+	departure time.Time,
+	flags sentPacketFlags,
+	probeClusterID int,
+) {
+	hasTWCC := false
+	if twccHdrExtID != 0 {
+		sequenceNumber := uint16(i.feedbackAdapter.nextSequenceNumber()) // nolint:gosec
+		if err := setTransportSequenceNumber(header, twccHdrExtID, sequenceNumber); err != nil {
+			i.log.Warnf("failed to set TWCC header extension: %v", err)
+		} else {
+			hasTWCC = true
+		}
+	}
+	size := header.MarshalSize() + payloadSize
+	i.feedbackAdapter.onPacketSent(
+		header.SSRC,
+		header.SequenceNumber,
+		hasTWCC,
+		size,
+		departure,
+		flags,
+		probeClusterID,
+	)
+	if flags&sentPacketPadding == 0 {
+		i.controller.OnPacketSent(size, departure)
+	}
+}
+
+// UnbindLocalStream implements interceptor.Interceptor.
+func (i *Interceptor) UnbindLocalStream(info *interceptor.StreamInfo) {
+	if i.pacer == nil {
+		return
+	}
+	i.sendLock.Lock()
+	defer i.sendLock.Unlock()
+	stream, ok := i.streams[info.SSRC]
+	if !ok {
+		return
+	}
+	if stream == i.paddingStream {
+		i.paddingStream = nil
+	}
+	delete(i.streams, stream.ssrc)
+	delete(i.streams, stream.rtxSSRC)
+}
+
+func (i *Interceptor) runPacer() {
+	defer i.wg.Done()
+	ticker := time.NewTicker(pacerInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-i.close:
+			return
+		case <-ticker.C:
+			i.releasePackets()
+		}
+	}
+}
+
+// releasePackets writes all packets the pacer releases at the current time.
+// Probes are only started once a video stream with an RTX stream is available
+// to carry the padding.
+func (i *Interceptor) releasePackets() {
+	i.sendLock.Lock()
+	defer i.sendLock.Unlock()
+
+	now := i.timestamp()
+	i.lock.Lock()
+	if i.paddingStream != nil {
+		for _, cluster := range i.controller.ProbeClusters(now) {
+			i.pacer.probe(cluster)
+		}
+	}
+	for {
+		packet, ok := i.pacer.dequeue(now)
+		if !ok {
+			break
+		}
+		i.sendBuffer = append(i.sendBuffer, packet)
+	}
+	i.controller.OnPacerQueue(i.pacer.queueSize)
+	i.lock.Unlock()
+
+	for j := range i.sendBuffer {
+		packet := &i.sendBuffer[j]
+		if i.prepare(packet) {
+			// The departure time is taken when the packet leaves the pacer.
+			i.lock.Lock()
+			i.onPacketSent(
+				&packet.header, packet.twccHdrExtID, len(packet.payload), i.timestamp(), packet.flags, packet.probeClusterID,
+			)
+			i.lock.Unlock()
+			if _, err := packet.writer.Write(&packet.header, packet.payload, packet.attributes); err != nil {
+				i.log.Warnf("failed to write paced packet: %v", err)
+			}
+		}
+		*packet = pacedPacket{}
+	}
+	i.sendBuffer = i.sendBuffer[:0]
+}
+
+// prepare rewrites the RTP sequence number of packet if it is a retransmission
+// or, if packet is a padding request from the pacer, turns it into a padding
+// packet on the RTX stream of the padding stream. It returns false if packet
+// should not be sent.
+func (i *Interceptor) prepare(packet *pacedPacket) bool {
+	if packet.writer == nil {
+		return i.preparePadding(packet)
+	}
+	if stream, ok := i.streams[packet.header.SSRC]; ok {
+		stream.onRelease(&packet.header)
+		i.paddingStream = stream
+	}
+
+	return true
+}
+
+func (i *Interceptor) preparePadding(packet *pacedPacket) bool {
+	stream := i.paddingStream
+	if stream == nil || !stream.started {
+		return false
+	}
+	if i.paddingPayload == nil {
+		// The padding is never modified, so all padding packets share it.
+		i.paddingPayload = make([]byte, maxPaddingSize)
+		i.paddingPayload[maxPaddingSize-1] = maxPaddingSize
+	}
+	packet.header = stream.nextPadding()
+	packet.payload = i.paddingPayload
+	packet.writer = stream.writer
+	packet.twccHdrExtID = stream.twccHdrExtID
+	packet.flags |= sentPacketRetransmission
+	packet.size = packet.header.MarshalSize() + len(packet.payload)
+
+	return true
+}
+
+// Close stops the pacer. Packets still queued in the pacer are dropped.
+func (i *Interceptor) Close() error {
+	select {
+	case <-i.close:
+	default:
+		close(i.close)
+	}
+	i.wg.Wait()
+
+	return nil
+}
+
+// packetFlags classifies an outgoing packet for the send history.
+func packetFlags(info *interceptor.StreamInfo, header *rtp.Header, payload []byte) sentPacketFlags {
+	var flags sentPacketFlags
+	if (info.SSRCRetransmission != 0 && header.SSRC == info.SSRCRetransmission) ||
+		(info.PayloadTypeRetransmission != 0 && header.PayloadType == info.PayloadTypeRetransmission) {
+		flags |= sentPacketRetransmission
+	}
+	// The payload either excludes the padding, which is then appended when
+	// the packet is marshaled, or includes it, in which case the last byte
+	// holds the size of the padding.
+	if header.Padding && (len(payload) == 0 || int(payload[len(payload)-1]) == len(payload)) {
+		flags |= sentPacketPadding
+	}
+
+	return flags
+}
+
+// BindRTCPReader implements interceptor.Interceptor.
+func (i *Interceptor) BindRTCPReader(reader interceptor.RTCPReader) interceptor.RTCPReader {
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+		if report, ok := attr.Get(rtpfb.CCFBAttributesKey).(rtpfb.Report); ok {
+			// An rtpfb.Interceptor read the RTCP packets before and already
+			// matched the feedback to the packets it tracked.
+			i.onFeedback(nil, &report)
+
+			return n, attr, err
+		}
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		pkts, err := attr.GetRTCPPackets(b[:n])
+		if err != nil {
+			return n, attr, err
+		}
+		i.onFeedback(pkts, nil)
+
+		return n, attr, err
+	})
+}
+
+// onFeedback updates the Controller with the feedback in pkts or, if report is
+// not nil, with the report of an rtpfb.Interceptor.
+func (i *Interceptor) onFeedback(pkts []rtcp.Packet, report *rtpfb.Report) {
+	now := i.timestamp()
+
+	i.lock.Lock()
+	var results []packetResult
+	var rtt time.Duration
+	if report != nil {
+		results, rtt = i.feedbackAdapter.onReport(now, *report)
+	} else {
+		results, rtt = i.feedbackAdapter.onFeedback(now, pkts)
+	}
+	if len(results) == 0 {
+		i.lock.Unlock()
+
+		return
+	}
+	previous := i.controller.TargetBitrate()
+	for _, result := range results {
+		switch {
+		case !result.received:
+			i.controller.OnPacketLost(result.size, result.departure)
+		case result.arrival.IsZero():
+			// The receiver acknowledged the packet, but could not report an
+			// arrival time, which makes it useless for delay-based estimation.
+		default:
+			i.controller.OnPacketAcked(result.sequenceNumber, result.size, result.departure, result.arrival)
+			if result.flags&sentPacketProbe != 0 {
+				i.controller.OnProbePacketAcked(result.probeClusterID, result.size, result.departure, result.arrival)
+			}
+		}
+	}
+	if rtt > 0 {
+		i.controller.OnRTT(rtt)
+	}
+	target := i.controller.Update(now)
+	if i.pacer != nil {
+		i.pacer.setTargetBitrate(target)
+	}
+	i.lock.Unlock()
+
+	if rtt > 0 && i.onRoundTripTime != nil {
+		i.onRoundTripTime(rtt)
+	}
+	if target != previous {
+		i.log.Tracef("target bitrate changed from %v to %v", previous, target)
+		if i.onTargetBitrateChange != nil {
+			i.onTargetBitrateChange(target)
+		}
+	}
+}
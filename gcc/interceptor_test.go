@@ -0,0 +1,609 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/nack"
+	"github.com/pion/interceptor/pkg/rtpfb"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewInterceptor(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		f, err := NewInterceptor(WithControllerOptions(WithInitialBitrate(500_000)))
+		assert.NoError(t, err)
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		assert.NotNil(t, i)
+		assert.NoError(t, i.Close())
+	})
+	t.Run("invalidControllerOptions", func(t *testing.T) {
+		f, err := NewInterceptor(WithControllerOptions(WithMinBitrate(-1)))
+		assert.ErrorIs(t, err, errInvalidBitrate)
+		assert.Nil(t, f)
+	})
+	t.Run("invalidPacingFactor", func(t *testing.T) {
+		f, err := NewInterceptor(WithPacing(0))
+		assert.ErrorIs(t, err, errInvalidPacerOption)
+		assert.Nil(t, f)
+	})
+	t.Run("invalidMaxPacerQueueDelay", func(t *testing.T) {
+		f, err := NewInterceptor(WithPacing(2.5), WithMaxPacerQueueDelay(-time.Second))
+		assert.ErrorIs(t, err, errInvalidPacerOption)
+		assert.Nil(t, f)
+	})
+}
+
+// feedbackReader returns an RTCP reader which returns the next packet from pkts
+// on each read.
+func feedbackReader(t *testing.T, pkts []rtcp.Packet) interceptor.RTCPReader {
+	t.Helper()
+	next := 0
+
+	return interceptor.RTCPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		buf, err := pkts[next].Marshal()
+		assert.NoError(t, err)
+		next++
+
+		return copy(b, buf), a, nil
+	})
+}
+
+func TestInterceptor(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	now := start
+	bitrates := []int{}
+	rtts := []time.Duration{}
+	f, err := NewInterceptor(
+		WithControllerOptions(WithInitialBitrate(1_000_000), WithMinBitrate(100_000)),
+		OnTargetBitrateChange(func(bitrate int) {
+			bitrates = append(bitrates, bitrate)
+		}),
+		OnRoundTripTime(func(rtt time.Duration) {
+			rtts = append(rtts, rtt)
+		}),
+		interceptorTimeFactory(func() time.Time { return now }),
+	)
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+
+	writer := gcc.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			return header.MarshalSize() + len(payload), nil
+		},
+	))
+
+	// Ten reports of 100 packets each, one packet every millisecond, every
+	// fifth packet is lost. Each report is sent 20ms after the last packet
+	// arrived, which is 20ms after it was sent.
+	reports := []rtcp.Packet{}
+	payload := make([]byte, 1188)
+	for r := range 10 {
+		report := &rtcp.CCFeedbackReport{
+			ReportTimestamp: uint32((r + 1) * 100 * (1 << 16) / 1000), // nolint:gosec
+			ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+				MediaSSRC:     1,
+				BeginSequence: uint16(r * 100), // nolint:gosec
+				MetricBlocks:  []rtcp.CCFeedbackMetricBlock{},
+			}},
+		}
+		for p := range 100 {
+			now = start.Add(time.Duration(r*100+p) * time.Millisecond)
+			_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: uint16(r*100 + p)}, payload, nil) // nolint:gosec
+			assert.NoError(t, err)
+			report.ReportBlocks[0].MetricBlocks = append(report.ReportBlocks[0].MetricBlocks, rtcp.CCFeedbackMetricBlock{
+				Received:          p%5 != 0,
+				ArrivalTimeOffset: uint16((99 - p) * 1024 / 1000), // nolint:gosec
+			})
+		}
+		reports = append(reports, report)
+	}
+
+	reader := gcc.BindRTCPReader(feedbackReader(t, reports))
+	buf := make([]byte, 1500)
+	for r := range reports {
+		now = start.Add(time.Duration(r+1)*100*time.Millisecond + 40*time.Millisecond)
+		_, _, err = reader.Read(buf, nil)
+		assert.NoError(t, err)
+	}
+	assert.NotEmpty(t, bitrates)
+	assert.Equal(t, bitrates[len(bitrates)-1], gcc.TargetBitrate())
+	assert.Less(t, gcc.TargetBitrate(), 1_000_000)
+	assert.Len(t, rtts, len(reports))
+	for _, rtt := range rtts {
+		assert.InDelta(t, 40*time.Millisecond, rtt, float64(time.Millisecond))
+	}
+}
+
+func TestInterceptorRTPFBReport(t *testing.T) {
+	now := time.Time{}.Add(time.Second)
+	f, err := NewInterceptor(interceptorTimeFactory(func() time.Time { return now }))
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+	factory, err := rtpfb.NewInterceptor()
+	assert.NoError(t, err)
+	feedback, err := factory.NewInterceptor("")
+	assert.NoError(t, err)
+
+	// The rtpfb.Interceptor is registered first, so it is closest to the
+	// transport.
+	info := &interceptor.StreamInfo{SSRC: 1}
+	writer := gcc.BindLocalStream(info, feedback.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			return header.MarshalSize() + len(payload), nil
+		},
+	)))
+	for seq := range uint16(3) {
+		_, err = writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: seq}, make([]byte, 1000), nil)
+		assert.NoError(t, err)
+	}
+	reader := gcc.BindRTCPReader(feedback.BindRTCPReader(feedbackReader(t, []rtcp.Packet{
+		&rtcp.CCFeedbackReport{
+			ReportTimestamp: 1 << 16,
+			ReportBlocks: []rtcp.CCFeedbackReportBlock{{
+				MediaSSRC:     1,
+				BeginSequence: 0,
+				MetricBlocks: []rtcp.CCFeedbackMetricBlock{
+					{Received: true, ArrivalTimeOffset: 100},
+					{Received: false},
+					{Received: true, ArrivalTimeOffset: 50},
+				},
+			}},
+		},
+		&rtcp.PictureLossIndication{SenderSSRC: 2, MediaSSRC: 1},
+	})))
+	now = now.Add(100 * time.Millisecond)
+	_, attr, err := reader.Read(make([]byte, 1500), nil)
+	assert.NoError(t, err)
+	_, ok := attr.Get(rtpfb.CCFBAttributesKey).(rtpfb.Report)
+	assert.True(t, ok)
+	// Only the report says that the second packet arrived.
+	attr = interceptor.Attributes{}
+	attr.Set(rtpfb.CCFBAttributesKey, rtpfb.Report{
+		PacketReports: []rtpfb.PacketReport{{SSRC: 1, RTPSequenceNumber: 1, Arrived: true, Arrival: time.Unix(1, 0)}},
+	})
+	_, _, err = reader.Read(make([]byte, 1500), attr)
+	assert.NoError(t, err)
+
+	gcc.lock.Lock()
+	defer gcc.lock.Unlock()
+	for seq := range uint64(3) {
+		packet, ok := gcc.feedbackAdapter.history.get(seq)
+		if assert.True(t, ok) {
+			assert.Equal(t, feedbackStatusReceived, packet.status)
+		}
+	}
+}
+
+func TestInterceptorIgnoresRTCPWithoutFeedback(t *testing.T) {
+	called := false
+	f, err := NewInterceptor(OnTargetBitrateChange(func(int) { called = true }))
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+
+	reader := gcc.BindRTCPReader(feedbackReader(t, []rtcp.Packet{
+		&rtcp.PictureLossIndication{SenderSSRC: 1, MediaSSRC: 2},
+	}))
+	_, _, err = reader.Read(make([]byte, 1500), nil)
+	assert.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, defaultInitialBitrate, gcc.TargetBitrate())
+}
+
+func TestInterceptorPacing(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	var lock sync.Mutex
+	now := start
+	f, err := NewInterceptor(
+		WithControllerOptions(WithInitialBitrate(800_000), WithProbing(false)),
+		WithPacing(1),
+		interceptorTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, gcc.Close())
+	}()
+
+	written := []uint16{}
+	writer := gcc.BindLocalStream(&interceptor.StreamInfo{SSRC: 1}, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			written = append(written, header.SequenceNumber)
+
+			return header.MarshalSize() + len(payload), nil
+		},
+	))
+	writtenPackets := func() []uint16 {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]uint16{}, written...)
+	}
+
+	// A burst of 1000 byte packets, at 800 kbps the pacer releases one
+	// packet every 10 ms.
+	payload := make([]byte, 988)
+	for seq := range uint16(5) {
+		n, err := writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: seq}, payload, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 1000, n)
+	}
+	assert.Equal(t, []uint16{0}, writtenPackets())
+	gcc.lock.Lock()
+	assert.Equal(t, 4000, gcc.pacer.queueSize)
+	gcc.lock.Unlock()
+
+	lock.Lock()
+	now = start.Add(20 * time.Millisecond)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		return len(writtenPackets()) == 3
+	}, time.Second, pacerInterval)
+
+	lock.Lock()
+	now = start.Add(40 * time.Millisecond)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		return len(writtenPackets()) == 5
+	}, time.Second, pacerInterval)
+	assert.Equal(t, []uint16{0, 1, 2, 3, 4}, writtenPackets())
+	gcc.lock.Lock()
+	assert.Zero(t, gcc.pacer.queueSize)
+	assert.Equal(t, 5, gcc.feedbackAdapter.history.len())
+	gcc.lock.Unlock()
+}
+
+func TestInterceptorPacingRetransmission(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	var lock sync.Mutex
+	now := start
+	f, err := NewInterceptor(
+		WithControllerOptions(WithInitialBitrate(800_000), WithProbing(false)),
+		WithPacing(1),
+		interceptorTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, gcc.Close())
+	}()
+
+	type sent struct {
+		ssrc           uint32
+		sequenceNumber uint16
+	}
+	written := []sent{}
+	info := &interceptor.StreamInfo{SSRC: 1, SSRCRetransmission: 2, PayloadTypeRetransmission: 97}
+	writer := gcc.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			written = append(written, sent{header.SSRC, header.SequenceNumber})
+
+			return header.MarshalSize() + len(payload), nil
+		},
+	))
+	writtenPackets := func() []sent {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]sent{}, written...)
+	}
+
+	// The retransmission is written behind four queued media packets, but is
+	// released before them.
+	payload := make([]byte, 988)
+	for seq := range uint16(5) {
+		_, err := writer.Write(&rtp.Header{SSRC: 1, SequenceNumber: seq}, payload, nil)
+		assert.NoError(t, err)
+	}
+	_, err = writer.Write(&rtp.Header{SSRC: 2, PayloadType: 97, SequenceNumber: 100}, payload, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []sent{{1, 0}}, writtenPackets())
+
+	lock.Lock()
+	now = start.Add(10 * time.Millisecond)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		return len(writtenPackets()) == 2
+	}, time.Second, pacerInterval)
+	assert.Equal(t, []sent{{1, 0}, {2, 100}}, writtenPackets())
+
+	lock.Lock()
+	now = start.Add(30 * time.Millisecond)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		return len(writtenPackets()) == 4
+	}, time.Second, pacerInterval)
+
+	lock.Lock()
+	now = start.Add(50 * time.Millisecond)
+	lock.Unlock()
+	assert.Eventually(t, func() bool {
+		return len(writtenPackets()) == 6
+	}, time.Second, pacerInterval)
+	assert.Equal(t, []sent{{1, 0}, {2, 100}, {1, 1}, {1, 2}, {1, 3}, {1, 4}}, writtenPackets())
+}
+
+func TestInterceptorPacingNACKResponder(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	var lock sync.Mutex
+	now := start
+	f, err := NewInterceptor(
+		WithControllerOptions(WithInitialBitrate(800_000), WithProbing(false)),
+		WithPacing(1),
+		interceptorTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, gcc.Close())
+	}()
+	responderFactory, err := nack.NewResponderInterceptor()
+	assert.NoError(t, err)
+	responder, err := responderFactory.NewInterceptor("")
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, responder.Close())
+	}()
+
+	type sent struct {
+		ssrc           uint32
+		sequenceNumber uint16
+	}
+	written := []sent{}
+	info := &interceptor.StreamInfo{
+		SSRC:                      1,
+		SSRCRetransmission:        2,
+		PayloadType:               96,
+		PayloadTypeRetransmission: 97,
+		MimeType:                  "video/VP8",
+		RTCPFeedback:              []interceptor.RTCPFeedback{{Type: "nack", Parameter: ""}},
+	}
+	// The GCC interceptor is registered before the NACK responder, so it is
+	// closer to the transport and paces the retransmissions as well.
+	writer := responder.BindLocalStream(info, gcc.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			lock.Lock()
+			defer lock.Unlock()
+			sequenceNumber := header.SequenceNumber
+			if header.SSRC == info.SSRCRetransmission {
+				// Record the original sequence number of the retransmission.
+				sequenceNumber = uint16(payload[0])<<8 | uint16(payload[1])
+			}
+			written = append(written, sent{header.SSRC, sequenceNumber})
+
+			return header.MarshalSize() + len(payload), nil
+		},
+	)))
+	writtenPackets := func() []sent {
+		lock.Lock()
+		defer lock.Unlock()
+
+		return append([]sent{}, written...)
+	}
+	nackReader := responder.BindRTCPReader(interceptor.RTCPReaderFunc(
+		func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+			buf, err := rtcp.Marshal([]rtcp.Packet{&rtcp.TransportLayerNack{
+				MediaSSRC: 1,
+				Nacks:     []rtcp.NackPair{{PacketID: 0}},
+			}})
+			if err != nil {
+				return 0, nil, err
+			}
+
+			return copy(b, buf), a, nil
+		},
+	))
+
+	// The retransmission of the first packet is written behind four queued
+	// media packets, but is released before them. The sequence numbers of
+	// the media packets are not changed.
+	payload := make([]byte, 988)
+	for seq := range uint16(5) {
+		_, err := writer.Write(&rtp.Header{SSRC: 1, PayloadType: 96, SequenceNumber: seq}, payload, nil)
+		assert.NoError(t, err)
+	}
+	_, _, err = nackReader.Read(make([]byte, 1500), nil)
+	assert.NoError(t, err)
+	// The responder writes the retransmission from its own goroutine.
+	assert.Eventually(t, func() bool {
+		gcc.lock.Lock()
+		defer gcc.lock.Unlock()
+
+		return gcc.pacer.queueSize > 4000
+	}, time.Second, pacerInterval)
+	assert.Equal(t, []sent{{1, 0}}, writtenPackets())
+
+	for range 20 {
+		lock.Lock()
+		now = now.Add(pacerInterval)
+		lock.Unlock()
+		gcc.releasePackets()
+	}
+	assert.Equal(t, []sent{{1, 0}, {2, 0}, {1, 1}, {1, 2}, {1, 3}, {1, 4}}, writtenPackets())
+}
+
+func TestInterceptorProbing(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	var lock sync.Mutex
+	now := start
+	f, err := NewInterceptor(
+		WithControllerOptions(WithInitialBitrate(800_000)),
+		WithPacing(1),
+		interceptorTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	gcc, err := f.newGCCInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, gcc.Close())
+	}()
+
+	type writtenPacket struct {
+		header rtp.Header
+		twcc   uint16
+	}
+	written := []writtenPacket{}
+	info := &interceptor.StreamInfo{
+		SSRC:                      1,
+		SSRCRetransmission:        2,
+		PayloadType:               96,
+		PayloadTypeRetransmission: 97,
+		MimeType:                  "video/VP8",
+		RTPHeaderExtensions:       []interceptor.RTPHeaderExtension{{URI: transportCCURI, ID: 1}},
+	}
+	writer := gcc.BindLocalStream(info, interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, _ interceptor.Attributes) (int, error) {
+			var ext rtp.TransportCCExtension
+			assert.NoError(t, ext.Unmarshal(header.GetExtension(1)))
+			written = append(written, writtenPacket{header: header.Clone(), twcc: ext.TransportSequence})
+
+			return header.MarshalSize() + len(payload), nil
+		},
+	))
+	// The interceptor adds the TWCC header extension to the packets itself.
+	write := func(sequenceNumber uint16) {
+		header := &rtp.Header{Version: 2, SSRC: 1, PayloadType: 96, SequenceNumber: sequenceNumber, Timestamp: 3000}
+		_, err := writer.Write(header, make([]byte, 1000), nil)
+		assert.NoError(t, err)
+	}
+
+	write(100)
+	// Step through the two initial probe clusters at 2.4 and 4.8 Mbps. Both
+	// goroutines releasing packets share sendLock, so all packets released at
+	// a point in time were written when releasePackets returns.
+	for range 100 {
+		lock.Lock()
+		now = now.Add(pacerInterval)
+		lock.Unlock()
+		gcc.releasePackets()
+	}
+	write(101)
+	// A retransmission continues the sequence numbers of the padding.
+	_, err = writer.Write(&rtp.Header{Version: 2, SSRC: 2, PayloadType: 97, SequenceNumber: 500}, make([]byte, 100), nil)
+	assert.NoError(t, err)
+	lock.Lock()
+	now = now.Add(pacerInterval)
+	lock.Unlock()
+	gcc.releasePackets()
+
+	gcc.sendLock.Lock()
+	defer gcc.sendLock.Unlock()
+	// 4500 and 9000 bytes of padding on the RTX stream, the sequence numbers
+	// of the media packets are not changed.
+	padding := 18 + 36
+	if assert.Len(t, written, padding+3) {
+		assert.Equal(t, uint16(100), written[0].header.SequenceNumber)
+		assert.Equal(t, uint16(101), written[padding+1].header.SequenceNumber)
+		for i, packet := range written {
+			assert.Equal(t, uint16(i), packet.twcc) // nolint:gosec
+			if i == 0 || i == padding+1 {
+				assert.Equal(t, uint32(1), packet.header.SSRC)
+				assert.Equal(t, uint8(96), packet.header.PayloadType)
+				assert.False(t, packet.header.Padding)
+
+				continue
+			}
+			assert.Equal(t, uint32(2), packet.header.SSRC)
+			assert.Equal(t, uint8(97), packet.header.PayloadType)
+			assert.Equal(t, uint16(min(i, padding+1)), packet.header.SequenceNumber) // nolint:gosec
+			assert.Equal(t, i != padding+2, packet.header.Padding)
+		}
+	}
+
+	gcc.lock.Lock()
+	defer gcc.lock.Unlock()
+	probes := map[int]int{}
+	for seq := range uint64(padding + 3) {
+		packet, ok := gcc.feedbackAdapter.history.get(seq)
+		if assert.True(t, ok) && packet.flags&sentPacketProbe != 0 {
+			assert.Equal(t, sentPacketProbe|sentPacketPadding|sentPacketRetransmission, packet.flags)
+			probes[packet.probeClusterID]++
+		}
+	}
+	assert.Equal(t, map[int]int{0: 18, 1: 36}, probes)
+}
+
+func TestPacketFlags(t *testing.T) {
+	info := &interceptor.StreamInfo{SSRC: 1, SSRCRetransmission: 2, PayloadTypeRetransmission: 97}
+	cases := []struct {
+		name     string
+		header   *rtp.Header
+		payload  []byte
+		expected sentPacketFlags
+	}{
+		{name: "media", header: &rtp.Header{SSRC: 1, PayloadType: 96}, payload: []byte{1, 2, 3}, expected: 0},
+		{
+			name:     "mediaWithPadding",
+			header:   &rtp.Header{SSRC: 1, PayloadType: 96, Padding: true},
+			payload:  []byte{1, 2, 0, 2},
+			expected: 0,
+		},
+		{
+			name:     "paddingOnly",
+			header:   &rtp.Header{SSRC: 1, PayloadType: 96, Padding: true},
+			payload:  []byte{0, 0, 3},
+			expected: sentPacketPadding,
+		},
+		{
+			name:     "paddingAppended",
+			header:   &rtp.Header{SSRC: 1, PayloadType: 96, Padding: true, PaddingSize: 200},
+			payload:  []byte{},
+			expected: sentPacketPadding,
+		},
+		{
+			name:     "retransmission",
+			header:   &rtp.Header{SSRC: 2, PayloadType: 97},
+			payload:  []byte{1, 2, 3},
+			expected: sentPacketRetransmission,
+		},
+		{
+			name:     "retransmissionPadding",
+			header:   &rtp.Header{SSRC: 2, PayloadType: 97, Padding: true},
+			payload:  []byte{0, 2},
+			expected: sentPacketRetransmission | sentPacketPadding,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, packetFlags(info, tc.header, tc.payload))
+		})
+	}
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+const (
+	// kalmanFrameRateWindow is the number of inter-arrival times over which
+	// the highest rate of groups is taken.
+	kalmanFrameRateWindow = 60
+
+	// kalmanOutlierFactor is the number of standard deviations of the
+	// measurement noise a residual is clamped to.
+	kalmanOutlierFactor = 3
+
+	// kalmanMinNoiseVariance is the lowest variance of the measurement noise.
+	kalmanMinNoiseVariance = 1.0
+
+	kalmanInitialErrorVariance = 0.1
+	kalmanInitialNoiseVariance = 50.0
+)
+
+// KalmanEstimatorOption configures the Kalman filter delay estimator.
+type KalmanEstimatorOption func(*kalmanEstimator)
+
+// KalmanProcessNoise sets the variance q of the process noise, which
+// determines how fast the estimate follows changes of the delay gradient. The
+// default is 1e-3.
+func KalmanProcessNoise(q float64) KalmanEstimatorOption {
+	return func(ke *kalmanEstimator) {
+		ke.processNoise = q
+	}
+}
+
+// KalmanChi sets the filter coefficient chi of the measurement noise
+// variance, which determines how fast the variance adapts to the observed
+// noise. The default is 0.01.
+func KalmanChi(chi float64) KalmanEstimatorOption {
+	return func(ke *kalmanEstimator) {
+		ke.chi = chi
+	}
+}
+
+// kalmanEstimator estimates the queuing delay variation m(i) with the scalar
+// Kalman filter described in section 5.3 of draft-ietf-rmcat-gcc-02. Unlike
+// the trendline, whose output is the slope of the delay scaled by a gain, which
+// the overuse detector also scales by the number of groups, the output is an
+// offset in milliseconds, which the overuse detector compares to the threshold
+// as is.
+type kalmanEstimator struct {
+	processNoise float64
+	chi          float64
+
+	offset        float64
+	errorVariance float64
+	noiseVariance float64
+
+	lastArrival       time.Time
+	interArrivalTimes []time.Duration
+}
+
+func newKalmanEstimator(options ...KalmanEstimatorOption) (*kalmanEstimator, error) {
+	ke := &kalmanEstimator{
+		processNoise:      1e-3,
+		chi:               0.01,
+		offset:            0,
+		errorVariance:     kalmanInitialErrorVariance,
+		noiseVariance:     kalmanInitialNoiseVariance,
+		lastArrival:       time.Time{},
+		interArrivalTimes: make([]time.Duration, 0, kalmanFrameRateWindow),
+	}
+	for _, opt := range options {
+		opt(ke)
+	}
+	if ke.processNoise < 0 {
+		return nil, fmt.Errorf("%w: process noise %v must not be negative", errInvalidDelayEstimatorOption, ke.processNoise)
+	}
+	if ke.chi <= 0 || ke.chi >= 1 {
+		return nil, fmt.Errorf("%w: chi %v must be in (0, 1)", errInvalidDelayEstimatorOption, ke.chi)
+	}
+
+	return ke, nil
+}
+
+// Update implements DelayEstimator.
+func (e *kalmanEstimator) Update(arrivalTime time.Time, interGroupDelay time.Duration) float64 {
+	if !e.lastArrival.IsZero() {
+		if len(e.interArrivalTimes) == kalmanFrameRateWindow {
+			copy(e.interArrivalTimes, e.interArrivalTimes[1:])
+			e.interArrivalTimes = e.interArrivalTimes[:len(e.interArrivalTimes)-1]
+		}
+		e.interArrivalTimes = append(e.interArrivalTimes, arrivalTime.Sub(e.lastArrival))
+	}
+	e.lastArrival = arrivalTime
+
+	residual := durationToMs(interGroupDelay) - e.offset
+	stdDev := math.Sqrt(e.noiseVariance)
+	residual = max(-kalmanOutlierFactor*stdDev, min(residual, kalmanOutlierFactor*stdDev))
+
+	// The draft takes f_max in 1/ms, so the variance adapts with chi per
+	// group if groups arrive every 30ms, like in libwebrtc.
+	alpha := math.Pow(1-e.chi, 30*e.shortestInterArrival()/1000)
+	e.noiseVariance = max(alpha*e.noiseVariance+(1-alpha)*residual*residual, kalmanMinNoiseVariance)
+
+	predictedVariance := e.errorVariance + e.processNoise
+	gain := predictedVariance / (e.noiseVariance + predictedVariance)
+	e.offset += gain * residual
+	e.errorVariance = (1 - gain) * predictedVariance
+
+	return e.offset
+}
+
+// Reset implements DelayEstimator.
+func (e *kalmanEstimator) Reset() {
+	e.offset = 0
+	e.errorVariance = kalmanInitialErrorVariance
+	e.noiseVariance = kalmanInitialNoiseVariance
+	e.lastArrival = time.Time{}
+	e.interArrivalTimes = e.interArrivalTimes[:0]
+}
+
+// shortestInterArrival returns the shortest time between two groups in
+// milliseconds over the last kalmanFrameRateWindow groups, which is 1/f_max
+// in the draft. Without any inter-arrival time, it assumes a group every 30
+// milliseconds.
+func (e *kalmanEstimator) shortestInterArrival() float64 {
+	shortest := time.Duration(0)
+	for _, d := range e.interArrivalTimes {
+		if d > 0 && (shortest == 0 || d < shortest) {
+			shortest = d
+		}
+	}
+	if shortest == 0 {
+		return 30
+	}
+
+	return durationToMs(shortest)
+}
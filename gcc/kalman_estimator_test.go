@@ -0,0 +1,167 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKalmanEstimator(t *testing.T) {
+	cases := []struct {
+		name            string
+		options         []KalmanEstimatorOption
+		interGroupDelay func(i int) time.Duration
+		expected        float64
+		delta           float64
+	}{
+		{
+			name:            "noDelay",
+			interGroupDelay: func(int) time.Duration { return 0 },
+			expected:        0,
+			delta:           0,
+		},
+		{
+			name:            "constantDelay",
+			interGroupDelay: func(int) time.Duration { return 2 * time.Millisecond },
+			expected:        2,
+			delta:           0.1,
+		},
+		{
+			name: "noise",
+			interGroupDelay: func(i int) time.Duration {
+				if i%2 == 0 {
+					return 5 * time.Millisecond
+				}
+
+				return -5 * time.Millisecond
+			},
+			expected: 0,
+			delta:    0.5,
+		},
+		{
+			name:            "negativeDelay",
+			interGroupDelay: func(int) time.Duration { return -time.Millisecond },
+			expected:        -1,
+			delta:           0.1,
+		},
+		{
+			name:            "noProcessNoise",
+			options:         []KalmanEstimatorOption{KalmanProcessNoise(0)},
+			interGroupDelay: func(int) time.Duration { return 2 * time.Millisecond },
+			// Without process noise, the gain decays and the estimate lags
+			// far behind.
+			expected: 1.64,
+			delta:    0.01,
+		},
+		{
+			name:            "slowNoiseAdaptation",
+			options:         []KalmanEstimatorOption{KalmanChi(0.001)},
+			interGroupDelay: func(int) time.Duration { return 2 * time.Millisecond },
+			expected:        2,
+			delta:           0.1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ke, err := newKalmanEstimator(tc.options...)
+			assert.NoError(t, err)
+			arrival := time.Time{}.Add(time.Second)
+			var offset float64
+			for i := 0; i < 1000; i++ {
+				arrival = arrival.Add(5 * time.Millisecond)
+				offset = ke.Update(arrival, tc.interGroupDelay(i))
+			}
+			assert.InDelta(t, tc.expected, offset, tc.delta)
+		})
+	}
+}
+
+func TestKalmanEstimatorClampsOutliers(t *testing.T) {
+	ke, err := newKalmanEstimator()
+	assert.NoError(t, err)
+	arrival := time.Time{}.Add(time.Second)
+	for i := 0; i < 100; i++ {
+		arrival = arrival.Add(5 * time.Millisecond)
+		ke.Update(arrival, 0)
+	}
+	// A single spike moves the estimate by at most the gain times three
+	// standard deviations of the noise.
+	before := ke.noiseVariance
+	offset := ke.Update(arrival.Add(5*time.Millisecond), time.Second)
+	assert.Greater(t, offset, 0.0)
+	assert.Less(t, offset, 3*before)
+}
+
+func TestKalmanEstimatorNoiseVariance(t *testing.T) {
+	for _, stdDev := range []float64{2, 4, 10} {
+		ke, err := newKalmanEstimator()
+		assert.NoError(t, err)
+		rng := rand.New(rand.NewPCG(1, 2)) // nolint:gosec
+		arrival := time.Time{}.Add(time.Second)
+		for i := 0; i < 5000; i++ {
+			arrival = arrival.Add(5 * time.Millisecond)
+			noise := rng.NormFloat64() * stdDev
+			ke.Update(arrival, time.Duration(noise*float64(time.Millisecond)))
+		}
+		// The clamping of outliers cuts off a small part of the variance.
+		assert.InEpsilon(t, stdDev*stdDev, ke.noiseVariance, 0.2, "standard deviation %v", stdDev)
+	}
+}
+
+func TestKalmanEstimatorShortestInterArrival(t *testing.T) {
+	ke, err := newKalmanEstimator()
+	assert.NoError(t, err)
+	assert.InDelta(t, 30.0, ke.shortestInterArrival(), 1e-9)
+
+	arrival := time.Time{}.Add(time.Second)
+	ke.Update(arrival, 0)
+	for _, d := range []time.Duration{20 * time.Millisecond, 10 * time.Millisecond, 40 * time.Millisecond} {
+		arrival = arrival.Add(d)
+		ke.Update(arrival, 0)
+	}
+	assert.InDelta(t, 10.0, ke.shortestInterArrival(), 1e-9)
+
+	// The shortest inter-arrival time leaves the window.
+	for i := 0; i < kalmanFrameRateWindow; i++ {
+		arrival = arrival.Add(50 * time.Millisecond)
+		ke.Update(arrival, 0)
+	}
+	assert.InDelta(t, 50.0, ke.shortestInterArrival(), 1e-9)
+}
+
+func TestKalmanEstimatorReset(t *testing.T) {
+	ke, err := newKalmanEstimator()
+	assert.NoError(t, err)
+	arrival := time.Time{}.Add(time.Second)
+	for range 100 {
+		arrival = arrival.Add(5 * time.Millisecond)
+		ke.Update(arrival, 2*time.Millisecond)
+	}
+	ke.Reset()
+	fresh, err := newKalmanEstimator()
+	assert.NoError(t, err)
+	assert.Equal(t, fresh, ke)
+}
+
+func TestKalmanEstimatorInvalidOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options []KalmanEstimatorOption
+	}{
+		{name: "negativeProcessNoise", options: []KalmanEstimatorOption{KalmanProcessNoise(-1)}},
+		{name: "zeroChi", options: []KalmanEstimatorOption{KalmanChi(0)}},
+		{name: "chiOne", options: []KalmanEstimatorOption{KalmanChi(1)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ke, err := newKalmanEstimator(tc.options...)
+			assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+			assert.Nil(t, ke)
+		})
+	}
+}
@@ -0,0 +1,323 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// lossObservationDuration is the shortest time over which the packets of
+	// an observation were sent.
+	lossObservationDuration = 250 * time.Millisecond
+	// lossObservationWindow is the number of most recent observations the
+	// estimate is fitted to, and lossMinObservations the number needed before
+	// there is an estimate.
+	lossObservationWindow = 20
+	lossMinObservations   = 3
+	// lossTemporalWeightFactor is the factor the weight of an observation is
+	// multiplied with for every newer observation.
+	lossTemporalWeightFactor = 0.9
+
+	// initialInherentLoss is the inherent loss rate assumed at startup.
+	initialInherentLoss = 0.01
+	// inherentLossLowerBound is the lowest inherent loss rate. The inherent
+	// loss rate is at most inherentLossUpperBoundOffset plus
+	// inherentLossUpperBoundBandwidthBalance divided by the bandwidth, so a
+	// high loss rate is only explained as inherent at low bitrates.
+	inherentLossLowerBound                 = 1e-3
+	inherentLossUpperBoundOffset           = 0.05
+	inherentLossUpperBoundBandwidthBalance = 75_000.0
+	// newtonIterations and newtonStepSize control the Newton's method fitting
+	// the inherent loss rate to the observations.
+	newtonIterations = 1
+	newtonStepSize   = 0.75
+
+	// If the average loss rate exceeds instantUpperBoundLossOffset, the
+	// estimate is at most instantUpperBoundBandwidthBalance divided by the
+	// loss rate in excess of the offset.
+	instantUpperBoundLossOffset       = 0.05
+	instantUpperBoundBandwidthBalance = 75_000.0
+
+	// lossCandidateIncreaseFactor and lossCandidateDecreaseFactor are the
+	// factors the current estimate is multiplied with to get the candidates
+	// next to it.
+	lossCandidateIncreaseFactor = 1.02
+	lossCandidateDecreaseFactor = 0.95
+
+	// higherBandwidthBiasFactor and higherLogBandwidthBiasFactor favor higher
+	// candidates while the average loss rate is below
+	// highBandwidthPreferenceLossThreshold, and lower candidates above it.
+	// bandwidthPreferenceSmoothingFactor smooths the change of preference
+	// around the threshold.
+	higherBandwidthBiasFactor            = 0.0002
+	higherLogBandwidthBiasFactor         = 0.02
+	highBandwidthPreferenceLossThreshold = 0.15
+	bandwidthPreferenceSmoothingFactor   = 0.002
+
+	// minLossProbability keeps the loss probability away from 0 and 1, where
+	// the log-likelihood is undefined.
+	minLossProbability = 1e-6
+)
+
+// lossObservation is the number of packets sent and lost over at least
+// lossObservationDuration and the rate at which they were sent.
+type lossObservation struct {
+	numPackets  int
+	numLost     int
+	sendingRate float64
+}
+
+// lossBasedBWEV2 estimates the loss-limited bandwidth like the LossBasedBweV2
+// of libwebrtc. It models the loss rate of each observation as an inherent
+// loss rate, which is caused by the path regardless of the sending rate, e.g.
+// on wireless links, plus the fraction of the sending rate exceeding the
+// loss-limited bandwidth. On every update, it evaluates a few candidate
+// bandwidths around the current estimate, fits the inherent loss rate for
+// each by Newton's method, and picks the candidate under which the observed
+// losses are most likely. Random loss is thus explained by the inherent loss
+// rate and only loss which grows with the sending rate lowers the estimate.
+type lossBasedBWEV2 struct {
+	min, max     float64
+	estimate     float64
+	inherentLoss float64
+
+	observations   []lossObservation
+	newObservation bool
+
+	packets        int
+	lost           int
+	sentBytes      int
+	firstDeparture time.Time
+	lastDeparture  time.Time
+}
+
+func newLossBasedBWEV2(initialRate, minRate, maxRate int) *lossBasedBWEV2 {
+	return &lossBasedBWEV2{
+		min:            float64(minRate),
+		max:            float64(maxRate),
+		estimate:       float64(initialRate),
+		inherentLoss:   initialInherentLoss,
+		observations:   make([]lossObservation, 0, lossObservationWindow),
+		newObservation: false,
+		packets:        0,
+		lost:           0,
+		sentBytes:      0,
+		firstDeparture: time.Time{},
+		lastDeparture:  time.Time{},
+	}
+}
+
+func (l *lossBasedBWEV2) onPacketAcked(size int, departure time.Time) {
+	l.onPacket(size, departure)
+}
+
+func (l *lossBasedBWEV2) onPacketLost(size int, departure time.Time) {
+	l.lost++
+	l.onPacket(size, departure)
+}
+
+// onPacket adds a packet to the next observation, which is complete once its
+// packets were sent over lossObservationDuration, no matter whether any of
+// them was received.
+func (l *lossBasedBWEV2) onPacket(size int, departure time.Time) {
+	l.packets++
+	l.sentBytes += size
+	if l.firstDeparture.IsZero() || departure.Before(l.firstDeparture) {
+		l.firstDeparture = departure
+	}
+	if departure.After(l.lastDeparture) {
+		l.lastDeparture = departure
+	}
+	if l.lastDeparture.Sub(l.firstDeparture) >= lossObservationDuration {
+		l.completeObservation()
+	}
+}
+
+// completeObservation adds the packets since the last observation as a new
+// observation.
+func (l *lossBasedBWEV2) completeObservation() {
+	observation := lossObservation{
+		numPackets:  l.packets,
+		numLost:     l.lost,
+		sendingRate: 8 * float64(l.sentBytes) / l.lastDeparture.Sub(l.firstDeparture).Seconds(),
+	}
+	if len(l.observations) == lossObservationWindow {
+		copy(l.observations, l.observations[1:])
+		l.observations = l.observations[:len(l.observations)-1]
+	}
+	l.observations = append(l.observations, observation)
+	l.newObservation = true
+
+	l.packets = 0
+	l.lost = 0
+	l.sentBytes = 0
+	l.firstDeparture = time.Time{}
+	l.lastDeparture = time.Time{}
+}
+
+func (l *lossBasedBWEV2) update(deliveryRate, delayBasedBitrate int) int {
+	if len(l.observations) < lossMinObservations {
+		// Without enough observations, the loss-based estimate does not
+		// limit the delay-based estimate.
+		l.estimate = max(l.min, min(float64(delayBasedBitrate), l.max))
+
+		return int(l.estimate)
+	}
+	if !l.newObservation {
+		return int(l.estimate)
+	}
+	l.newObservation = false
+
+	averageLoss := l.averageLoss()
+	bestObjective := math.Inf(-1)
+	bestEstimate := l.estimate
+	bestInherentLoss := l.inherentLoss
+	for _, candidate := range l.candidates(float64(deliveryRate), float64(delayBasedBitrate), averageLoss) {
+		inherentLoss := l.fitInherentLoss(candidate)
+		objective := l.objective(candidate, inherentLoss, averageLoss)
+		if objective > bestObjective {
+			bestObjective = objective
+			bestEstimate = candidate
+			bestInherentLoss = inherentLoss
+		}
+	}
+	l.estimate = bestEstimate
+	l.inherentLoss = bestInherentLoss
+
+	return int(l.estimate)
+}
+
+// candidates returns the bandwidths to evaluate. The current estimate comes
+// first, so it is kept if other candidates explain the observations equally
+// well.
+func (l *lossBasedBWEV2) candidates(deliveryRate, delayBasedBitrate, averageLoss float64) []float64 {
+	candidates := make([]float64, 0, 5)
+	candidates = append(candidates,
+		l.estimate,
+		lossCandidateIncreaseFactor*l.estimate,
+		lossCandidateDecreaseFactor*l.estimate,
+		delayBasedBitrate,
+	)
+	if deliveryRate > 0 {
+		candidates = append(candidates, deliveryRate)
+	}
+	upperBound := min(l.max, delayBasedBitrate)
+	if averageLoss > instantUpperBoundLossOffset {
+		upperBound = min(upperBound, instantUpperBoundBandwidthBalance/(averageLoss-instantUpperBoundLossOffset))
+	}
+	for i, candidate := range candidates {
+		if candidate > l.estimate && l.inherentLoss < averageLoss {
+			// More loss than can be explained by the inherent loss rate, so
+			// the path does not support a higher bitrate.
+			candidate = l.estimate
+		}
+		candidates[i] = max(l.min, min(candidate, upperBound))
+	}
+
+	return candidates
+}
+
+// averageLoss returns the loss rate of the observations, weighted like in the
+// objective.
+func (l *lossBasedBWEV2) averageLoss() float64 {
+	packets := 0.0
+	lost := 0.0
+	weight := 1.0
+	for i := len(l.observations) - 1; i >= 0; i-- {
+		packets += weight * float64(l.observations[i].numPackets)
+		lost += weight * float64(l.observations[i].numLost)
+		weight *= lossTemporalWeightFactor
+	}
+	if packets == 0 {
+		return 0
+	}
+
+	return lost / packets
+}
+
+// fitInherentLoss returns the inherent loss rate under which the observations
+// are most likely if the loss-limited bandwidth is bandwidth, starting from
+// the current inherent loss rate.
+func (l *lossBasedBWEV2) fitInherentLoss(bandwidth float64) float64 {
+	upperBound := min(inherentLossUpperBoundOffset+inherentLossUpperBoundBandwidthBalance/bandwidth, 1)
+	inherentLoss := l.inherentLoss
+	for i := 0; i < newtonIterations; i++ {
+		first := 0.0
+		second := 0.0
+		weight := 1.0
+		for j := len(l.observations) - 1; j >= 0; j-- {
+			observation := l.observations[j]
+			probability := lossProbability(inherentLoss, bandwidth, observation.sendingRate)
+			// Derivative of the loss probability with respect to the
+			// inherent loss rate.
+			derivative := 1.0
+			if observation.sendingRate > bandwidth {
+				derivative = bandwidth / observation.sendingRate
+			}
+			lost := float64(observation.numLost)
+			received := float64(observation.numPackets - observation.numLost)
+			first += weight * derivative * (lost/probability - received/(1-probability))
+			second -= weight * derivative * derivative *
+				(lost/(probability*probability) + received/((1-probability)*(1-probability)))
+			weight *= lossTemporalWeightFactor
+		}
+		if second < 0 {
+			inherentLoss -= newtonStepSize * first / second
+		}
+		inherentLoss = max(inherentLossLowerBound, min(inherentLoss, upperBound))
+	}
+
+	return inherentLoss
+}
+
+// objective returns the weighted log-likelihood of the observations if the
+// loss-limited bandwidth is bandwidth, plus a bias towards higher or lower
+// bandwidths depending on the average loss rate.
+func (l *lossBasedBWEV2) objective(bandwidth, inherentLoss, averageLoss float64) float64 {
+	bias := highBandwidthBias(bandwidth, averageLoss)
+	objective := 0.0
+	weight := 1.0
+	for i := len(l.observations) - 1; i >= 0; i-- {
+		observation := l.observations[i]
+		probability := lossProbability(inherentLoss, bandwidth, observation.sendingRate)
+		lost := float64(observation.numLost)
+		received := float64(observation.numPackets - observation.numLost)
+		objective += weight * (lost*math.Log(probability) + received*math.Log(1-probability) +
+			bias*float64(observation.numPackets))
+		weight *= lossTemporalWeightFactor
+	}
+
+	return objective
+}
+
+func (l *lossBasedBWEV2) setMaxBitrate(rate int) {
+	l.max = float64(rate)
+	l.estimate = min(l.estimate, l.max)
+}
+
+func (l *lossBasedBWEV2) onProbeResult(bitrate int) {
+	l.estimate = max(l.estimate, float64(bitrate))
+}
+
+// lossProbability returns the probability of a packet sent at sendingRate to
+// be lost if the path has the inherent loss rate and drops the fraction of the
+// sending rate exceeding bandwidth.
+func lossProbability(inherentLoss, bandwidth, sendingRate float64) float64 {
+	probability := inherentLoss
+	if sendingRate > bandwidth {
+		probability += (1 - inherentLoss) * (sendingRate - bandwidth) / sendingRate
+	}
+
+	return max(minLossProbability, min(probability, 1-minLossProbability))
+}
+
+func highBandwidthBias(bandwidth, averageLoss float64) float64 {
+	distance := highBandwidthPreferenceLossThreshold - averageLoss
+	adjustment := distance / (bandwidthPreferenceSmoothingFactor + math.Abs(distance))
+	kbps := bandwidth / 1000
+
+	return adjustment * (higherBandwidthBiasFactor*kbps + higherLogBandwidthBiasFactor*math.Log(1+kbps))
+}
@@ -0,0 +1,121 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// observeLoss reports the packets of one observation sent at rate, of which
+// every lossInterval-th packet is lost, and returns the time after the last
+// packet. A lossInterval of 0 means no loss.
+func observeLoss(l *lossBasedBWEV2, start time.Time, rate, lossInterval int) time.Time {
+	return observe(l, start, rate, func(i int) bool {
+		return lossInterval > 0 && i%lossInterval == 0
+	})
+}
+
+// observe reports the packets of one observation sent at rate, of which the
+// i-th is lost if lost(i) is true, and returns the time after the last packet.
+func observe(l *lossBasedBWEV2, start time.Time, rate int, lost func(i int) bool) time.Time {
+	const size = 1000
+	interval := time.Duration(8 * size * int(time.Second) / rate)
+	departure := start
+	for i := 1; departure.Sub(start) <= lossObservationDuration; i++ {
+		if lost(i) {
+			l.onPacketLost(size, departure)
+		} else {
+			l.onPacketAcked(size, departure)
+		}
+		departure = departure.Add(interval)
+	}
+
+	return departure
+}
+
+func TestLossBasedBWEV2(t *testing.T) {
+	t.Run("followsDelayBasedEstimateWithoutObservations", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		assert.Equal(t, 1_000_000, l.update(0, 1_000_000))
+		assert.Equal(t, 10_000_000, l.update(0, 20_000_000))
+	})
+
+	t.Run("followsDelayBasedEstimateWithoutLoss", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		now := time.Time{}
+		for i := 0; i < 10; i++ {
+			now = observeLoss(l, now, 1_000_000, 0)
+			assert.Equal(t, 1_200_000, l.update(1_000_000, 1_200_000))
+		}
+	})
+
+	t.Run("keepsEstimateOnRandomLoss", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		now := time.Time{}
+		estimate := 0
+		for i := 0; i < 2*lossObservationWindow; i++ {
+			// About 14% of the packets are lost at any sending rate.
+			now = observeLoss(l, now, 500_000, 7)
+			estimate = l.update(430_000, 1_000_000)
+		}
+		assert.GreaterOrEqual(t, estimate, 500_000)
+	})
+
+	t.Run("decreasesOnCongestionLoss", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		now := time.Time{}
+		estimate := 0
+		for i := 0; i < 2*lossObservationWindow; i++ {
+			// The path drops everything above 1 Mbps.
+			now = observeLoss(l, now, 1_000_000, 0)
+			estimate = l.update(1_000_000, 2_000_000)
+			now = observeLoss(l, now, 1_250_000, 5)
+			estimate = l.update(1_000_000, 2_000_000)
+		}
+		assert.Less(t, estimate, 1_200_000)
+		assert.Greater(t, estimate, 800_000)
+	})
+
+	t.Run("decreasesOnFullLoss", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		now := time.Time{}
+		for i := 0; i < lossObservationWindow; i++ {
+			now = observeLoss(l, now, 1_000_000, 0)
+			assert.Equal(t, 1_200_000, l.update(1_000_000, 1_200_000))
+		}
+		// Nothing arrives anymore, so every observation only has losses.
+		estimate := 0
+		for i := 0; i < lossMinObservations; i++ {
+			now = observeLoss(l, now, 1_000_000, 1)
+			assert.True(t, l.newObservation)
+			estimate = l.update(0, 1_200_000)
+		}
+		observation := l.observations[len(l.observations)-1]
+		assert.Equal(t, observation.numPackets, observation.numLost)
+		assert.InDelta(t, 1_000_000, observation.sendingRate, 50_000)
+		assert.Less(t, estimate, 500_000)
+		assert.GreaterOrEqual(t, estimate, 10_000)
+	})
+
+	t.Run("decreasesOnBurstLoss", func(t *testing.T) {
+		l := newLossBasedBWEV2(300_000, 10_000, 10_000_000)
+		now := time.Time{}
+		estimate := 0
+		for i := 0; i < 2*lossObservationWindow; i++ {
+			// The path drops everything above 1 Mbps in bursts, after its
+			// queue overflowed.
+			now = observeLoss(l, now, 1_000_000, 0)
+			estimate = l.update(1_000_000, 2_000_000)
+			now = observe(l, now, 1_250_000, func(i int) bool {
+				return i > 24 && i <= 32
+			})
+			estimate = l.update(1_000_000, 2_000_000)
+		}
+		assert.Less(t, estimate, 1_200_000)
+		assert.Greater(t, estimate, 800_000)
+	})
+}
@@ -3,49 +3,190 @@
 
 package gcc
 
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// lossController computes the loss-based estimate from the packets reported
+// as received or lost by the remote peer.
+type lossController interface {
+	// onPacketAcked adds a received packet of size bytes sent at departure.
+	onPacketAcked(size int, departure time.Time)
+	// onPacketLost adds a lost packet of size bytes sent at departure.
+	onPacketLost(size int, departure time.Time)
+	// update returns the loss-based estimate in bits per second. deliveryRate
+	// is the rate at which the remote peer receives packets and
+	// delayBasedBitrate the current delay-based estimate.
+	update(deliveryRate, delayBasedBitrate int) int
+	// setMaxBitrate changes the highest estimate.
+	setMaxBitrate(rate int)
+	// onProbeResult raises the estimate to bitrate, which a probe showed the
+	// path supports.
+	onProbeResult(bitrate int)
+}
+
+var errInvalidLossRateControllerOption = errors.New("invalid loss rate controller option")
+
+// LossRateControllerOption configures the default loss-based controller.
+type LossRateControllerOption func(*lossRateController)
+
+// LossRateControllerLowLossThreshold sets the loss rate below which the
+// estimate is increased. The default is 0.02.
+func LossRateControllerLowLossThreshold(threshold float64) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.lowLossThreshold = threshold
+	}
+}
+
+// LossRateControllerHighLossThreshold sets the loss rate above which the
+// estimate is decreased. The default is 0.1.
+func LossRateControllerHighLossThreshold(threshold float64) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.highLossThreshold = threshold
+	}
+}
+
+// LossRateControllerIncreaseFactor sets the factor the estimate is multiplied
+// with on every update while the loss rate is low. The default is 1.05.
+func LossRateControllerIncreaseFactor(factor float64) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.increaseFactor = factor
+	}
+}
+
+// LossRateControllerDecreaseGain sets the fraction of the loss rate by which
+// the estimate is decreased on every update while the loss rate is high. The
+// default is 0.5, i.e. a loss rate of 20% decreases the estimate by 10%.
+func LossRateControllerDecreaseGain(gain float64) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.decreaseGain = gain
+	}
+}
+
+// LossRateControllerDeliveryRateCap sets the factor the delivery rate is
+// multiplied with to get the highest estimate an increase may reach. The
+// default is 1.5.
+func LossRateControllerDeliveryRateCap(factor float64) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.deliveryRateCap = factor
+	}
+}
+
+// LossRateControllerMinPackets sets the number of reported packets needed to
+// compute a loss rate. Updates with fewer packets keep the estimate and the
+// packets are counted towards the next update. The default is 1.
+func LossRateControllerMinPackets(packets int) LossRateControllerOption {
+	return func(l *lossRateController) {
+		l.minPackets = packets
+	}
+}
+
+// lossRateController increases the estimate while few packets are lost and
+// decreases it in proportion to the loss rate while many packets are lost.
 type lossRateController struct {
 	bitrate  int
 	min, max float64
 
+	lowLossThreshold  float64
+	highLossThreshold float64
+	increaseFactor    float64
+	decreaseGain      float64
+	deliveryRateCap   float64
+	minPackets        int
+
 	packetsSinceLastUpdate int
 	lostSinceLastUpdate    int
 }
 
-func newLossRateController(initialRate, minRate, maxRate int) *lossRateController {
-	return &lossRateController{
+func newLossRateController(
+	initialRate, minRate, maxRate int,
+	options ...LossRateControllerOption,
+) (*lossRateController, error) {
+	lrc := &lossRateController{
 		bitrate:                initialRate,
 		min:                    float64(minRate),
 		max:                    float64(maxRate),
+		lowLossThreshold:       0.02,
+		highLossThreshold:      0.1,
+		increaseFactor:         1.05,
+		decreaseGain:           0.5,
+		deliveryRateCap:        1.5,
+		minPackets:             1,
 		packetsSinceLastUpdate: 0,
 		lostSinceLastUpdate:    0,
 	}
+	for _, opt := range options {
+		opt(lrc)
+	}
+	if err := lrc.validate(); err != nil {
+		return nil, err
+	}
+
+	return lrc, nil
 }
 
-func (l *lossRateController) onPacketAcked() {
+func (l *lossRateController) validate() error {
+	if l.lowLossThreshold < 0 || l.highLossThreshold > 1 || l.lowLossThreshold > l.highLossThreshold {
+		return fmt.Errorf(
+			"%w: loss thresholds must satisfy 0 <= low <= high <= 1, got low %v and high %v",
+			errInvalidLossRateControllerOption, l.lowLossThreshold, l.highLossThreshold,
+		)
+	}
+	if l.increaseFactor < 1 {
+		return fmt.Errorf(
+			"%w: increase factor %v must be at least 1",
+			errInvalidLossRateControllerOption, l.increaseFactor,
+		)
+	}
+	if l.decreaseGain <= 0 || l.decreaseGain > 1 {
+		return fmt.Errorf(
+			"%w: decrease gain %v must be in (0, 1]",
+			errInvalidLossRateControllerOption, l.decreaseGain,
+		)
+	}
+	if l.deliveryRateCap <= 0 {
+		return fmt.Errorf(
+			"%w: delivery rate cap %v must be positive",
+			errInvalidLossRateControllerOption, l.deliveryRateCap,
+		)
+	}
+	if l.minPackets < 1 {
+		return fmt.Errorf(
+			"%w: min packets %d must be at least 1",
+			errInvalidLossRateControllerOption, l.minPackets,
+		)
+	}
+
+	return nil
+}
+
+func (l *lossRateController) onPacketAcked(int, time.Time) {
 	l.packetsSinceLastUpdate++
 }
 
-func (l *lossRateController) onPacketLost() {
+func (l *lossRateController) onPacketLost(int, time.Time) {
 	l.packetsSinceLastUpdate++
 	l.lostSinceLastUpdate++
 }
 
-func (l *lossRateController) update(lastDeliveryRate int) int {
-	// Without any reported packets there is no loss rate to act on.
-	if l.packetsSinceLastUpdate == 0 {
+func (l *lossRateController) update(lastDeliveryRate, _ int) int {
+	// Without enough reported packets there is no loss rate to act on.
+	if l.packetsSinceLastUpdate < l.minPackets {
 		return l.bitrate
 	}
 	lossRate := float64(l.lostSinceLastUpdate) / float64(l.packetsSinceLastUpdate)
 	var target float64
-	if lossRate > 0.1 {
-		target = float64(l.bitrate) * (1 - 0.5*lossRate)
+	if lossRate > l.highLossThreshold {
+		target = float64(l.bitrate) * (1 - l.decreaseGain*lossRate)
 		target = max(target, l.min)
-	} else if lossRate < 0.02 {
-		target = float64(l.bitrate) * 1.05
-		// Cap at 1.5 times the previously delivered rate to ensure we don't
-		// increase the target rate indefinitely, while being application
-		// limited.
-		target = min(target, 1.5*float64(lastDeliveryRate))
+	} else if lossRate < l.lowLossThreshold {
+		target = float64(l.bitrate) * l.increaseFactor
+		// Cap at a multiple of the previously delivered rate to ensure we
+		// don't increase the target rate indefinitely, while being
+		// application limited.
+		target = min(target, l.deliveryRateCap*float64(lastDeliveryRate))
 		// Cap at previous target rate. In case lastDeliveryRate was much lower
 		// than our target, we don't want to decrease the target rate.
 		target = max(target, float64(l.bitrate))
@@ -61,3 +202,12 @@ func (l *lossRateController) update(lastDeliveryRate int) int {
 
 	return l.bitrate
 }
+
+func (l *lossRateController) setMaxBitrate(rate int) {
+	l.max = float64(rate)
+	l.bitrate = min(l.bitrate, rate)
+}
+
+func (l *lossRateController) onProbeResult(bitrate int) {
+	l.bitrate = max(l.bitrate, bitrate)
+}
@@ -6,6 +6,7 @@ package gcc
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,6 +14,7 @@ import (
 func TestLossRateController(t *testing.T) {
 	cases := []struct {
 		init, min, max int
+		options        []LossRateControllerOption
 		acked          int
 		lost           int
 		deliveredRate  int
@@ -73,17 +75,153 @@ func TestLossRateController(t *testing.T) {
 			deliveredRate: 90_000,
 			expectedRate:  100_000,
 		},
+		{
+			init:          100_000,
+			min:           50_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerHighLossThreshold(0.15)},
+			acked:         89,
+			lost:          11,
+			deliveredRate: 90_000,
+			expectedRate:  100_000,
+		},
+		{
+			init:          100_000,
+			min:           50_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerDecreaseGain(1)},
+			acked:         89,
+			lost:          11,
+			deliveredRate: 90_000,
+			expectedRate:  89_000,
+		},
+		{
+			init:          100_000,
+			min:           100_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerLowLossThreshold(0.05)},
+			acked:         96,
+			lost:          4,
+			deliveredRate: 100_000,
+			expectedRate:  105_000,
+		},
+		{
+			init:          100_000,
+			min:           100_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerIncreaseFactor(1.2)},
+			acked:         100,
+			lost:          0,
+			deliveredRate: 100_000,
+			expectedRate:  120_000,
+		},
+		{
+			init:          100_000,
+			min:           100_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerDeliveryRateCap(1.1)},
+			acked:         100,
+			lost:          0,
+			deliveredRate: 100_000,
+			expectedRate:  105_000,
+		},
+		{
+			init:          100_000,
+			min:           100_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerDeliveryRateCap(1.02)},
+			acked:         100,
+			lost:          0,
+			deliveredRate: 100_000,
+			expectedRate:  102_000,
+		},
+		{
+			init:          100_000,
+			min:           50_000,
+			max:           1_000_000,
+			options:       []LossRateControllerOption{LossRateControllerMinPackets(101)},
+			acked:         0,
+			lost:          100,
+			deliveredRate: 100_000,
+			expectedRate:  100_000,
+		},
 	}
 	for i, tc := range cases {
 		t.Run(fmt.Sprintf("%v", i), func(t *testing.T) {
-			lrc := newLossRateController(tc.init, tc.min, tc.max)
+			lrc, err := newLossRateController(tc.init, tc.min, tc.max, tc.options...)
+			assert.NoError(t, err)
 			for i := 0; i < tc.acked; i++ {
-				lrc.onPacketAcked()
+				lrc.onPacketAcked(1200, time.Time{})
 			}
 			for i := 0; i < tc.lost; i++ {
-				lrc.onPacketLost()
+				lrc.onPacketLost(1200, time.Time{})
 			}
-			assert.Equal(t, tc.expectedRate, lrc.update(tc.deliveredRate))
+			assert.Equal(t, tc.expectedRate, lrc.update(tc.deliveredRate, 0))
+		})
+	}
+}
+
+func TestLossRateControllerMinPackets(t *testing.T) {
+	lrc, err := newLossRateController(100_000, 50_000, 1_000_000, LossRateControllerMinPackets(20))
+	assert.NoError(t, err)
+	for i := 0; i < 10; i++ {
+		lrc.onPacketLost(1200, time.Time{})
+	}
+	assert.Equal(t, 100_000, lrc.update(100_000, 0))
+
+	// The packets of the previous update count towards this one.
+	for i := 0; i < 10; i++ {
+		lrc.onPacketAcked(1200, time.Time{})
+	}
+	assert.Equal(t, 75_000, lrc.update(100_000, 0))
+}
+
+func TestLossRateControllerInvalidOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options []LossRateControllerOption
+	}{
+		{
+			name:    "negativeLowThreshold",
+			options: []LossRateControllerOption{LossRateControllerLowLossThreshold(-0.1)},
+		},
+		{
+			name:    "highThresholdAboveOne",
+			options: []LossRateControllerOption{LossRateControllerHighLossThreshold(1.5)},
+		},
+		{
+			name: "lowAboveHighThreshold",
+			options: []LossRateControllerOption{
+				LossRateControllerLowLossThreshold(0.2),
+				LossRateControllerHighLossThreshold(0.1),
+			},
+		},
+		{
+			name:    "increaseFactorBelowOne",
+			options: []LossRateControllerOption{LossRateControllerIncreaseFactor(0.9)},
+		},
+		{
+			name:    "zeroDecreaseGain",
+			options: []LossRateControllerOption{LossRateControllerDecreaseGain(0)},
+		},
+		{
+			name:    "decreaseGainAboveOne",
+			options: []LossRateControllerOption{LossRateControllerDecreaseGain(1.5)},
+		},
+		{
+			name:    "zeroDeliveryRateCap",
+			options: []LossRateControllerOption{LossRateControllerDeliveryRateCap(0)},
+		},
+		{
+			name:    "zeroMinPackets",
+			options: []LossRateControllerOption{LossRateControllerMinPackets(0)},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			lrc, err := newLossRateController(100_000, 50_000, 1_000_000, tc.options...)
+			assert.ErrorIs(t, err, errInvalidLossRateControllerOption)
+			assert.Nil(t, lrc)
 		})
 	}
 }
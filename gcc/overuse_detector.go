@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// maxNumDeltas caps the number of samples the trend is scaled by.
+	maxNumDeltas = 60
+
+	// maxAdaptOffset is the distance between the modified trend and the
+	// threshold, in milliseconds, above which the threshold is not adapted to
+	// avoid reacting to sudden spikes.
+	maxAdaptOffset = 15.0
+
+	// maxThresholdUpdateInterval caps the time between two threshold updates.
+	maxThresholdUpdateInterval = 100 * time.Millisecond
+
+	minThreshold = 6.0
+	maxThreshold = 600.0
+)
+
+type overuseDetectorOption func(*overuseDetector)
+
+func overuseDetectorInitialThreshold(threshold float64) overuseDetectorOption {
+	return func(d *overuseDetector) {
+		d.threshold = threshold
+	}
+}
+
+// overuseDetectorScaleTrend sets whether the trend is multiplied by the number
+// of samples seen, up to maxNumDeltas, before it is compared to the threshold.
+// The slope of the trendline needs it, other estimates are compared as is.
+func overuseDetectorScaleTrend(scale bool) overuseDetectorOption {
+	return func(d *overuseDetector) {
+		d.scaleTrend = scale
+	}
+}
+
+func overuseDetectorOverusingTimeThreshold(threshold time.Duration) overuseDetectorOption {
+	return func(d *overuseDetector) {
+		d.overusingTimeThreshold = threshold
+	}
+}
+
+// overuseDetector compares the delay gradient to the adaptive threshold gamma
+// described in section 5.4 of draft-ietf-rmcat-gcc-02 and signals overuse only
+// if the gradient stays above the threshold for at least
+// overusingTimeThreshold.
+type overuseDetector struct {
+	kUp                    float64
+	kDown                  float64
+	overusingTimeThreshold time.Duration
+	scaleTrend             bool
+
+	threshold  float64
+	lastUpdate time.Time
+	numDeltas  int
+	prevTrend  float64
+
+	// timeOverUsing is negative if the last sample was not above the
+	// threshold.
+	timeOverUsing  time.Duration
+	overuseCounter int
+
+	usage usage
+}
+
+func newOveruseDetector(options ...overuseDetectorOption) *overuseDetector {
+	d := &overuseDetector{
+		kUp:                    0.0087,
+		kDown:                  0.039,
+		overusingTimeThreshold: 10 * time.Millisecond,
+		scaleTrend:             true,
+		threshold:              12.5,
+		lastUpdate:             time.Time{},
+		numDeltas:              0,
+		prevTrend:              0,
+		timeOverUsing:          -1,
+		overuseCounter:         0,
+		usage:                  usageNormal,
+	}
+	for _, opt := range options {
+		opt(d)
+	}
+
+	return d
+}
+
+// update adds the trend measured for a group that departed
+// interDepartureTime after the previous one and returns the current usage
+// signal.
+func (d *overuseDetector) update(now time.Time, trend float64, interDepartureTime time.Duration) usage {
+	d.numDeltas++
+	if d.numDeltas < 2 {
+		d.prevTrend = trend
+
+		return usageNormal
+	}
+	modifiedTrend := trend
+	if d.scaleTrend {
+		modifiedTrend *= float64(min(d.numDeltas, maxNumDeltas))
+	}
+
+	switch {
+	case modifiedTrend > d.threshold:
+		if d.timeOverUsing < 0 {
+			// Assume the overuse started half way between the previous and
+			// the current sample.
+			d.timeOverUsing = interDepartureTime / 2
+		} else {
+			d.timeOverUsing += interDepartureTime
+		}
+		d.overuseCounter++
+		if d.timeOverUsing > d.overusingTimeThreshold && d.overuseCounter > 1 && trend >= d.prevTrend {
+			d.timeOverUsing = 0
+			d.overuseCounter = 0
+			d.usage = usageOver
+		}
+	case modifiedTrend < -d.threshold:
+		d.timeOverUsing = -1
+		d.overuseCounter = 0
+		d.usage = usageUnder
+	default:
+		d.timeOverUsing = -1
+		d.overuseCounter = 0
+		d.usage = usageNormal
+	}
+	d.prevTrend = trend
+	d.updateThreshold(now, modifiedTrend)
+
+	return d.usage
+}
+
+func (d *overuseDetector) updateThreshold(now time.Time, modifiedTrend float64) {
+	if d.lastUpdate.IsZero() {
+		d.lastUpdate = now
+	}
+	absTrend := math.Abs(modifiedTrend)
+	if absTrend > d.threshold+maxAdaptOffset {
+		d.lastUpdate = now
+
+		return
+	}
+	k := d.kUp
+	if absTrend < d.threshold {
+		k = d.kDown
+	}
+	delta := min(now.Sub(d.lastUpdate), maxThresholdUpdateInterval)
+	d.threshold += k * (absTrend - d.threshold) * durationToMs(delta)
+	d.threshold = max(minThreshold, min(d.threshold, maxThreshold))
+	d.lastUpdate = now
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOveruseDetector(t *testing.T) {
+	type sample struct {
+		trend              float64
+		interDepartureTime time.Duration
+		expected           usage
+	}
+	cases := []struct {
+		name    string
+		samples []sample
+	}{
+		{
+			name: "firstSampleIsNormal",
+			samples: []sample{
+				{trend: 100, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+			},
+		},
+		{
+			name: "belowThresholdIsNormal",
+			samples: []sample{
+				{trend: 0.1, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 0.1, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: -0.1, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+			},
+		},
+		{
+			name: "overuseAfterOverusingTimeThreshold",
+			samples: []sample{
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageOver},
+			},
+		},
+		{
+			name: "noOveruseOnDecreasingTrend",
+			samples: []sample{
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 4, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+			},
+		},
+		{
+			name: "overuseIsKeptUntilTrendDrops",
+			samples: []sample{
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageOver},
+				{trend: 5, interDepartureTime: 5 * time.Millisecond, expected: usageOver},
+				{trend: 0, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+			},
+		},
+		{
+			name: "underuse",
+			samples: []sample{
+				{trend: -5, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+				{trend: -5, interDepartureTime: 5 * time.Millisecond, expected: usageUnder},
+				{trend: 0, interDepartureTime: 5 * time.Millisecond, expected: usageNormal},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// All samples are added at the same time, so the threshold stays at
+			// its lower bound.
+			od := newOveruseDetector(
+				overuseDetectorInitialThreshold(minThreshold),
+				overuseDetectorOverusingTimeThreshold(10*time.Millisecond),
+			)
+			for _, s := range tc.samples {
+				assert.Equal(t, s.expected, od.update(time.Time{}, s.trend, s.interDepartureTime))
+			}
+		})
+	}
+}
+
+func TestOveruseDetectorScalesTrendByNumDeltas(t *testing.T) {
+	od := newOveruseDetector()
+	// A trend of 1 exceeds the initial threshold of 12.5 only once it is
+	// scaled by at least 13 samples.
+	for i := 1; i < 13; i++ {
+		assert.Equal(t, usageNormal, od.update(time.Time{}, -1, 0), "sample %v", i)
+	}
+	assert.Equal(t, usageUnder, od.update(time.Time{}, -1, 0))
+}
+
+func TestOveruseDetectorWithoutScaling(t *testing.T) {
+	od := newOveruseDetector(overuseDetectorScaleTrend(false))
+	// The trend is compared to the initial threshold of 12.5 as is, however
+	// many samples were seen.
+	for i := 0; i < 100; i++ {
+		assert.Equal(t, usageNormal, od.update(time.Time{}, -12, 0), "sample %v", i)
+	}
+	assert.Equal(t, usageUnder, od.update(time.Time{}, -13, 0))
+}
+
+func TestOveruseDetectorThreshold(t *testing.T) {
+	type update struct {
+		offset            time.Duration
+		modifiedTrend     float64
+		expectedThreshold float64
+	}
+	cases := []struct {
+		name    string
+		updates []update
+	}{
+		{
+			name: "firstUpdateKeepsThreshold",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+			},
+		},
+		{
+			name: "decreasesTowardsSmallTrend",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: 10 * time.Millisecond, modifiedTrend: 0, expectedThreshold: 7.625},
+			},
+		},
+		{
+			name: "increasesTowardsLargeTrend",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: 10 * time.Millisecond, modifiedTrend: 20, expectedThreshold: 13.1525},
+			},
+		},
+		{
+			name: "usesAbsoluteTrend",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: 10 * time.Millisecond, modifiedTrend: -20, expectedThreshold: 13.1525},
+			},
+		},
+		{
+			name: "ignoresSpikes",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: 10 * time.Millisecond, modifiedTrend: 30, expectedThreshold: 12.5},
+			},
+		},
+		{
+			name: "capsTimeDelta",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: time.Second, modifiedTrend: 20, expectedThreshold: 19.025},
+			},
+		},
+		{
+			name: "clampsToMinThreshold",
+			updates: []update{
+				{offset: 0, modifiedTrend: 0, expectedThreshold: 12.5},
+				{offset: time.Second, modifiedTrend: 0, expectedThreshold: minThreshold},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			od := newOveruseDetector()
+			start := time.Time{}.Add(time.Second)
+			for _, u := range tc.updates {
+				od.updateThreshold(start.Add(u.offset), u.modifiedTrend)
+				assert.InDelta(t, u.expectedThreshold, od.threshold, 0.0001)
+			}
+		})
+	}
+}
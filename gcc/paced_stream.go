@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+// pacedStream tracks a local video stream with an RTX stream whose packets go
+// through the pacer. Padding for probing is sent on the RTX stream, so the RTP
+// sequence numbers of the media stream are never changed. To insert padding
+// into the RTX stream, the sequence numbers of the retransmissions released
+// after the padding are shifted by the number of padding packets inserted
+// before them.
+type pacedStream struct {
+	ssrc           uint32
+	rtxSSRC        uint32
+	rtxPayloadType uint8
+	twccHdrExtID   uint8
+	writer         interceptor.RTPWriter
+
+	sequenceNumberOffset uint16
+	lastSequenceNumber   uint16
+	// rtxStarted is set once a packet was sent on the RTX stream and
+	// retransmitted once a retransmission was sent on it.
+	rtxStarted    bool
+	retransmitted bool
+	lastTimestamp uint32
+	started       bool
+}
+
+func newPacedStream(info *interceptor.StreamInfo, twccHdrExtID uint8, writer interceptor.RTPWriter) *pacedStream {
+	return &pacedStream{
+		ssrc:                 info.SSRC,
+		rtxSSRC:              info.SSRCRetransmission,
+		rtxPayloadType:       info.PayloadTypeRetransmission,
+		twccHdrExtID:         twccHdrExtID,
+		writer:               writer,
+		sequenceNumberOffset: 0,
+		lastSequenceNumber:   0,
+		rtxStarted:           false,
+		retransmitted:        false,
+		lastTimestamp:        0,
+		started:              false,
+	}
+}
+
+// onRelease is called with the header of every packet about to be sent on the
+// stream or its RTX stream. It rewrites the sequence numbers of
+// retransmissions to follow the padding sent before them.
+func (s *pacedStream) onRelease(header *rtp.Header) {
+	if header.SSRC != s.rtxSSRC {
+		s.lastTimestamp = header.Timestamp
+		s.started = true
+
+		return
+	}
+	if !s.retransmitted && s.rtxStarted {
+		// Continue the sequence numbers of the padding sent before the first
+		// retransmission.
+		s.sequenceNumberOffset = s.lastSequenceNumber + 1 - header.SequenceNumber
+	}
+	header.SequenceNumber += s.sequenceNumberOffset
+	s.lastSequenceNumber = header.SequenceNumber
+	s.rtxStarted = true
+	s.retransmitted = true
+}
+
+// nextPadding returns the header of a padding packet following the last
+// packet sent on the RTX stream.
+func (s *pacedStream) nextPadding() rtp.Header {
+	s.sequenceNumberOffset++
+	s.lastSequenceNumber++
+	s.rtxStarted = true
+
+	return rtp.Header{
+		Version:        2,
+		Padding:        true,
+		PayloadType:    s.rtxPayloadType,
+		SequenceNumber: s.lastSequenceNumber,
+		Timestamp:      s.lastTimestamp,
+		SSRC:           s.rtxSSRC,
+	}
+}
+
+func setTransportSequenceNumber(header *rtp.Header, id uint8, sequenceNumber uint16) error {
+	ext, err := (&rtp.TransportCCExtension{TransportSequence: sequenceNumber}).Marshal()
+	if err != nil {
+		return err
+	}
+
+	return header.SetExtension(id, ext)
+}
@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+const (
+	defaultMaxPacerQueueDelay = 2 * time.Second
+
+	// pacerInterval is the interval at which the pacer releases packets.
+	pacerInterval = 5 * time.Millisecond
+	// maxPacerBudget is the longest time for which unused budget is kept. It
+	// allows the pacer to catch up after a late tick, but prevents bursts
+	// after idle periods.
+	maxPacerBudget = 2 * pacerInterval
+	// minPacerDrainTime is the shortest time the pacer plans to drain its
+	// queue in when packets are about to exceed the maximum queue delay.
+	minPacerDrainTime = time.Millisecond
+
+	// maxPaddingSize is the largest padding an RTP packet can carry.
+	maxPaddingSize = 255
+)
+
+type pacerPriority int
+
+const (
+	// pacerPriorityHigh is used for audio and packets on RTX streams.
+	pacerPriorityHigh pacerPriority = iota
+	pacerPriorityNormal
+	numPacerPriorities
+)
+
+type pacedPacket struct {
+	header     rtp.Header
+	payload    []byte
+	attributes interceptor.Attributes
+	writer     interceptor.RTPWriter
+
+	twccHdrExtID   uint8
+	flags          sentPacketFlags
+	probeClusterID int
+	size           int
+	enqueued       time.Time
+}
+
+// pacer is a leaky bucket which releases queued packets at a multiple of the
+// target bitrate. Packets are released in order of priority and in FIFO order
+// within the same priority. If the oldest packet would wait longer than
+// maxQueueDelay at the pacing rate, the rate is increased so the queue drains
+// in time.
+//
+// While probe clusters are pending, packets are released at the bitrate of
+// the first cluster instead and padding is released whenever the queue is
+// empty, until the cluster is complete. The caller fills padding packets,
+// which have no writer, with the padding of an RTP packet on any stream.
+type pacer struct {
+	factor        float64
+	maxQueueDelay time.Duration
+
+	targetBitrate int
+	budget        float64
+	lastUpdate    time.Time
+
+	queues    [numPacerPriorities][]pacedPacket
+	queueSize int
+
+	probes           []ProbeCluster
+	probeSentPackets int
+	probeSentBytes   int
+}
+
+func newPacer(factor float64, maxQueueDelay time.Duration, targetBitrate int) *pacer {
+	return &pacer{
+		factor:        factor,
+		maxQueueDelay: maxQueueDelay,
+		targetBitrate: targetBitrate,
+		budget:        0,
+		lastUpdate:    time.Time{},
+		queues:        [numPacerPriorities][]pacedPacket{},
+		queueSize:     0,
+
+		probes:           nil,
+		probeSentPackets: 0,
+		probeSentBytes:   0,
+	}
+}
+
+func (p *pacer) setTargetBitrate(bitrate int) {
+	p.targetBitrate = bitrate
+}
+
+func (p *pacer) probe(cluster ProbeCluster) {
+	p.probes = append(p.probes, cluster)
+}
+
+func (p *pacer) enqueue(packet pacedPacket, priority pacerPriority) {
+	p.queues[priority] = append(p.queues[priority], packet)
+	p.queueSize += packet.size
+}
+
+// len returns the number of queued packets.
+func (p *pacer) len() int {
+	n := 0
+	for _, q := range p.queues {
+		n += len(q)
+	}
+
+	return n
+}
+
+// oldest returns the time the oldest queued packet was enqueued.
+func (p *pacer) oldest() time.Time {
+	oldest := time.Time{}
+	for _, q := range p.queues {
+		if len(q) > 0 && (oldest.IsZero() || q[0].enqueued.Before(oldest)) {
+			oldest = q[0].enqueued
+		}
+	}
+
+	return oldest
+}
+
+// pacingRate returns the rate in bits per second at which packets are
+// released at now.
+func (p *pacer) pacingRate(now time.Time) float64 {
+	rate := p.factor * float64(p.targetBitrate)
+	if len(p.probes) > 0 {
+		rate = float64(p.probes[0].Bitrate)
+	}
+	if p.queueSize == 0 {
+		return rate
+	}
+	drainTime := max(p.maxQueueDelay-now.Sub(p.oldest()), minPacerDrainTime)
+
+	return max(rate, float64(8*p.queueSize)/drainTime.Seconds())
+}
+
+// update adds the budget accumulated since the last update.
+func (p *pacer) update(now time.Time) {
+	if p.lastUpdate.IsZero() {
+		p.lastUpdate = now
+	}
+	elapsed := now.Sub(p.lastUpdate)
+	if elapsed <= 0 {
+		return
+	}
+	p.lastUpdate = now
+	rate := p.pacingRate(now)
+	p.budget = min(p.budget+rate*elapsed.Seconds()/8, rate*maxPacerBudget.Seconds()/8)
+}
+
+// dequeue returns the next packet if the budget allows sending it at now. The
+// budget may become negative, so packets larger than the budget are not held
+// back forever.
+func (p *pacer) dequeue(now time.Time) (pacedPacket, bool) {
+	p.update(now)
+	if p.budget < 0 {
+		return pacedPacket{}, false
+	}
+	packet, ok := p.pop()
+	if !ok && len(p.probes) == 0 {
+		return pacedPacket{}, false
+	}
+	if !ok {
+		packet = pacedPacket{flags: sentPacketPadding, size: maxPaddingSize}
+	}
+	p.budget -= float64(packet.size)
+	if len(p.probes) > 0 {
+		packet.flags |= sentPacketProbe
+		packet.probeClusterID = p.probes[0].ID
+		p.onProbeSent(packet.size)
+	}
+
+	return packet, true
+}
+
+func (p *pacer) pop() (pacedPacket, bool) {
+	for i, q := range p.queues {
+		if len(q) == 0 {
+			continue
+		}
+		packet := q[0]
+		q[0] = pacedPacket{}
+		p.queues[i] = q[1:]
+		p.queueSize -= packet.size
+
+		return packet, true
+	}
+
+	return pacedPacket{}, false
+}
+
+func (p *pacer) onProbeSent(size int) {
+	p.probeSentPackets++
+	p.probeSentBytes += size
+	cluster := p.probes[0]
+	if p.probeSentPackets >= cluster.MinPackets && p.probeSentBytes >= cluster.MinBytes {
+		p.probes = p.probes[1:]
+		p.probeSentPackets = 0
+		p.probeSentBytes = 0
+	}
+}
@@ -0,0 +1,177 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func pacedPacketOfSize(sequenceNumber uint16, size int, enqueued time.Time) pacedPacket {
+	return pacedPacket{
+		header:   rtp.Header{SequenceNumber: sequenceNumber},
+		size:     size,
+		enqueued: enqueued,
+	}
+}
+
+// releaseTimes enqueues count packets of size bytes at start and returns the
+// offsets from start at which the pacer releases them when it is polled every
+// pacerInterval.
+func releaseTimes(p *pacer, start time.Time, count, size int, until time.Duration) []time.Duration {
+	for i := range count {
+		p.enqueue(pacedPacketOfSize(uint16(i), size, start), pacerPriorityNormal) // nolint:gosec
+	}
+	times := []time.Duration{}
+	for d := time.Duration(0); d <= until; d += pacerInterval {
+		for {
+			_, ok := p.dequeue(start.Add(d))
+			if !ok {
+				break
+			}
+			times = append(times, d)
+		}
+	}
+
+	return times
+}
+
+func TestPacer(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	cases := []struct {
+		name          string
+		factor        float64
+		maxQueueDelay time.Duration
+		targetBitrate int
+		count         int
+		size          int
+		until         time.Duration
+		expected      []time.Duration
+	}{
+		{
+			name:          "pacingRate",
+			factor:        1,
+			maxQueueDelay: time.Second,
+			targetBitrate: 800_000,
+			count:         4,
+			size:          1000,
+			until:         100 * time.Millisecond,
+			expected:      []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond},
+		},
+		{
+			name:          "pacingFactor",
+			factor:        2,
+			maxQueueDelay: time.Second,
+			targetBitrate: 800_000,
+			count:         4,
+			size:          1000,
+			until:         100 * time.Millisecond,
+			expected:      []time.Duration{0, 5 * time.Millisecond, 10 * time.Millisecond, 15 * time.Millisecond},
+		},
+		{
+			name:          "maxQueueDelay",
+			factor:        1,
+			maxQueueDelay: 100 * time.Millisecond,
+			targetBitrate: 8_000,
+			count:         10,
+			size:          1000,
+			until:         time.Second,
+			// At the target bitrate, the last packet would be released after
+			// 9 seconds.
+			expected: []time.Duration{
+				0, 15 * time.Millisecond, 20 * time.Millisecond, 35 * time.Millisecond, 40 * time.Millisecond,
+				55 * time.Millisecond, 60 * time.Millisecond, 75 * time.Millisecond, 80 * time.Millisecond,
+				95 * time.Millisecond,
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newPacer(tc.factor, tc.maxQueueDelay, tc.targetBitrate)
+			assert.Equal(t, tc.expected, releaseTimes(p, start, tc.count, tc.size, tc.until))
+			assert.Zero(t, p.len())
+			assert.Zero(t, p.queueSize)
+		})
+	}
+}
+
+func TestPacerPriority(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newPacer(1, time.Second, 800_000)
+	p.enqueue(pacedPacketOfSize(1, 100, start), pacerPriorityNormal)
+	p.enqueue(pacedPacketOfSize(2, 100, start), pacerPriorityNormal)
+	p.enqueue(pacedPacketOfSize(3, 100, start), pacerPriorityHigh)
+	p.enqueue(pacedPacketOfSize(4, 100, start), pacerPriorityHigh)
+	assert.Equal(t, 4, p.len())
+	assert.Equal(t, 400, p.queueSize)
+
+	order := []uint16{}
+	for d := time.Duration(0); p.len() > 0; d += pacerInterval {
+		for {
+			packet, ok := p.dequeue(start.Add(d))
+			if !ok {
+				break
+			}
+			order = append(order, packet.header.SequenceNumber)
+		}
+	}
+	assert.Equal(t, []uint16{3, 4, 1, 2}, order)
+}
+
+func TestPacerLimitsBudgetAfterIdle(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newPacer(1, time.Second, 800_000)
+	p.enqueue(pacedPacketOfSize(0, 1000, start), pacerPriorityNormal)
+	_, ok := p.dequeue(start)
+	assert.True(t, ok)
+
+	// After an idle second, the budget only covers maxPacerBudget.
+	now := start.Add(time.Second)
+	for i := range 10 {
+		p.enqueue(pacedPacketOfSize(uint16(i), 1000, now), pacerPriorityNormal) // nolint:gosec
+	}
+	released := 0
+	for {
+		if _, ok := p.dequeue(now); !ok {
+			break
+		}
+		released++
+	}
+	assert.Equal(t, 2, released)
+}
+
+func TestPacerProbe(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newPacer(1, time.Second, 100_000)
+	p.enqueue(pacedPacketOfSize(1, 500, start), pacerPriorityNormal)
+	p.probe(ProbeCluster{ID: 7, Bitrate: 800_000, MinPackets: 5, MinBytes: 1500})
+
+	released := []pacedPacket{}
+	for d := time.Duration(0); d <= 100*time.Millisecond; d += pacerInterval {
+		for {
+			packet, ok := p.dequeue(start.Add(d))
+			if !ok {
+				break
+			}
+			released = append(released, packet)
+		}
+	}
+	// The media packet and four padding packets complete the cluster.
+	if assert.Len(t, released, 5) {
+		assert.Equal(t, uint16(1), released[0].header.SequenceNumber)
+		assert.Equal(t, sentPacketProbe, released[0].flags)
+		for _, packet := range released {
+			assert.Equal(t, 7, packet.probeClusterID)
+		}
+		for _, packet := range released[1:] {
+			assert.Nil(t, packet.writer)
+			assert.Equal(t, sentPacketProbe|sentPacketPadding, packet.flags)
+			assert.Equal(t, maxPaddingSize, packet.size)
+		}
+	}
+	assert.Empty(t, p.probes)
+}
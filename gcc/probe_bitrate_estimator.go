@@ -0,0 +1,140 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+)
+
+const (
+	// minReceivedProbesRatio and minReceivedBytesRatio are the fractions of
+	// the packets and bytes of a cluster that must be acknowledged before the
+	// cluster yields a result. They allow for some loss.
+	minReceivedProbesRatio = 0.8
+	minReceivedBytesRatio  = 0.8
+
+	// maxProbeInterval is the longest time over which the packets of a
+	// cluster may be sent or received for the cluster to yield a result.
+	maxProbeInterval = time.Second
+
+	// maxValidProbeRatio is the highest ratio of receive rate to send rate
+	// that is considered valid. Higher ratios are caused by packets being
+	// held back and released in a burst on the path.
+	maxValidProbeRatio = 2.0
+
+	// minRatioForUnsaturatedLink is the lowest ratio of receive rate to send
+	// rate at which the path is assumed to not have been saturated by the
+	// probe.
+	minRatioForUnsaturatedLink = 0.9
+
+	// targetUtilizationFraction is the fraction of the receive rate used as
+	// the result if the probe saturated the path.
+	targetUtilizationFraction = 0.95
+
+	// maxClusterHistory is the time after which clusters without new
+	// acknowledged packets are forgotten.
+	maxClusterHistory = time.Second
+)
+
+type probeAggregate struct {
+	firstDeparture time.Time
+	lastDeparture  time.Time
+	firstArrival   time.Time
+	lastArrival    time.Time
+
+	lastDepartureSize int
+	firstArrivalSize  int
+	size              int
+	count             int
+}
+
+// probeBitrateEstimator computes the bitrate supported by the path from the
+// send and receive rates of the packets of a probe cluster, in the same way as
+// the ProbeBitrateEstimator of libwebrtc. The size of the last packet sent is
+// excluded from the send rate and the size of the first packet received is
+// excluded from the receive rate, because the packets mark the ends of the
+// intervals.
+type probeBitrateEstimator struct {
+	clusters map[int]*probeAggregate
+}
+
+func newProbeBitrateEstimator() *probeBitrateEstimator {
+	return &probeBitrateEstimator{
+		clusters: map[int]*probeAggregate{},
+	}
+}
+
+// onProbePacketAcked adds an acknowledged packet of cluster and returns the
+// estimated bitrate once enough packets of the cluster were acknowledged.
+func (e *probeBitrateEstimator) onProbePacketAcked(
+	cluster ProbeCluster,
+	size int,
+	departure, arrival time.Time,
+) (int, bool) {
+	e.evict(arrival)
+
+	aggregate, ok := e.clusters[cluster.ID]
+	if !ok {
+		aggregate = &probeAggregate{
+			firstDeparture:    departure,
+			lastDeparture:     departure,
+			firstArrival:      arrival,
+			lastArrival:       arrival,
+			lastDepartureSize: size,
+			firstArrivalSize:  size,
+			size:              0,
+			count:             0,
+		}
+		e.clusters[cluster.ID] = aggregate
+	}
+	if departure.Before(aggregate.firstDeparture) {
+		aggregate.firstDeparture = departure
+	}
+	if !departure.Before(aggregate.lastDeparture) {
+		aggregate.lastDeparture = departure
+		aggregate.lastDepartureSize = size
+	}
+	if arrival.Before(aggregate.firstArrival) {
+		aggregate.firstArrival = arrival
+		aggregate.firstArrivalSize = size
+	}
+	if arrival.After(aggregate.lastArrival) {
+		aggregate.lastArrival = arrival
+	}
+	aggregate.size += size
+	aggregate.count++
+
+	if float64(aggregate.count) < minReceivedProbesRatio*float64(cluster.MinPackets) ||
+		float64(aggregate.size) < minReceivedBytesRatio*float64(cluster.MinBytes) {
+		return 0, false
+	}
+
+	sendInterval := aggregate.lastDeparture.Sub(aggregate.firstDeparture)
+	receiveInterval := aggregate.lastArrival.Sub(aggregate.firstArrival)
+	if sendInterval <= 0 || sendInterval > maxProbeInterval ||
+		receiveInterval <= 0 || receiveInterval > maxProbeInterval {
+		return 0, false
+	}
+	sendRate := 8 * float64(aggregate.size-aggregate.lastDepartureSize) / sendInterval.Seconds()
+	receiveRate := 8 * float64(aggregate.size-aggregate.firstArrivalSize) / receiveInterval.Seconds()
+	if receiveRate > maxValidProbeRatio*sendRate {
+		return 0, false
+	}
+	bitrate := min(sendRate, receiveRate)
+	if receiveRate < minRatioForUnsaturatedLink*sendRate {
+		// The path could not forward the probe at the send rate, so the
+		// receive rate is close to its capacity.
+		bitrate = targetUtilizationFraction * receiveRate
+	}
+
+	return int(bitrate), true
+}
+
+func (e *probeBitrateEstimator) evict(now time.Time) {
+	for id, aggregate := range e.clusters {
+		if now.Sub(aggregate.lastArrival) > maxClusterHistory {
+			delete(e.clusters, id)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbeBitrateEstimator(t *testing.T) {
+	type probePacket struct {
+		departure time.Duration
+		arrival   time.Duration
+	}
+	cluster := ProbeCluster{ID: 1, Bitrate: 800_000, MinPackets: 4, MinBytes: 4000}
+	cases := []struct {
+		name     string
+		cluster  ProbeCluster
+		packets  []probePacket
+		expected int
+		ok       bool
+	}{
+		{
+			name:    "oneCluster",
+			cluster: cluster,
+			packets: []probePacket{
+				{0, 10 * time.Millisecond},
+				{10 * time.Millisecond, 20 * time.Millisecond},
+				{20 * time.Millisecond, 30 * time.Millisecond},
+				{30 * time.Millisecond, 40 * time.Millisecond},
+			},
+			expected: 800_000,
+			ok:       true,
+		},
+		{
+			name:    "tooFewPackets",
+			cluster: ProbeCluster{ID: 1, Bitrate: 800_000, MinPackets: 5, MinBytes: 5000},
+			packets: []probePacket{
+				{0, 10 * time.Millisecond},
+				{10 * time.Millisecond, 20 * time.Millisecond},
+				{20 * time.Millisecond, 30 * time.Millisecond},
+			},
+			expected: 0,
+			ok:       false,
+		},
+		{
+			name:    "toleratesLoss",
+			cluster: ProbeCluster{ID: 1, Bitrate: 800_000, MinPackets: 5, MinBytes: 5000},
+			packets: []probePacket{
+				{0, 10 * time.Millisecond},
+				{10 * time.Millisecond, 20 * time.Millisecond},
+				{20 * time.Millisecond, 30 * time.Millisecond},
+				{30 * time.Millisecond, 40 * time.Millisecond},
+			},
+			expected: 800_000,
+			ok:       true,
+		},
+		{
+			name:    "receivedFasterThanSent",
+			cluster: cluster,
+			packets: []probePacket{
+				{0, 100 * time.Millisecond},
+				{10 * time.Millisecond, 101 * time.Millisecond},
+				{20 * time.Millisecond, 102 * time.Millisecond},
+				{30 * time.Millisecond, 103 * time.Millisecond},
+			},
+			expected: 0,
+			ok:       false,
+		},
+		{
+			name:    "saturatedLink",
+			cluster: cluster,
+			packets: []probePacket{
+				{0, 0},
+				{10 * time.Millisecond, 40 * time.Millisecond},
+				{20 * time.Millisecond, 80 * time.Millisecond},
+				{30 * time.Millisecond, 120 * time.Millisecond},
+			},
+			expected: 190_000,
+			ok:       true,
+		},
+		{
+			name:    "reordered",
+			cluster: cluster,
+			packets: []probePacket{
+				{0, 10 * time.Millisecond},
+				{20 * time.Millisecond, 30 * time.Millisecond},
+				{10 * time.Millisecond, 20 * time.Millisecond},
+				{30 * time.Millisecond, 40 * time.Millisecond},
+			},
+			expected: 800_000,
+			ok:       true,
+		},
+		{
+			name:    "sentTooSlowly",
+			cluster: cluster,
+			packets: []probePacket{
+				{0, 10 * time.Millisecond},
+				{10 * time.Millisecond, 20 * time.Millisecond},
+				{20 * time.Millisecond, 30 * time.Millisecond},
+				{2 * time.Second, 40 * time.Millisecond},
+			},
+			expected: 0,
+			ok:       false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Time{}.Add(time.Second)
+			e := newProbeBitrateEstimator()
+			var bitrate int
+			var ok bool
+			for _, p := range tc.packets {
+				bitrate, ok = e.onProbePacketAcked(tc.cluster, 1000, start.Add(p.departure), start.Add(p.arrival))
+			}
+			assert.Equal(t, tc.ok, ok)
+			assert.InDelta(t, tc.expected, bitrate, 1)
+		})
+	}
+}
+
+func TestProbeBitrateEstimatorEvictsOldClusters(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	e := newProbeBitrateEstimator()
+	e.onProbePacketAcked(ProbeCluster{ID: 1, MinPackets: 5}, 1000, start, start)
+	e.onProbePacketAcked(ProbeCluster{ID: 2, MinPackets: 5}, 1000, start, start.Add(500*time.Millisecond))
+	assert.Len(t, e.clusters, 2)
+	e.onProbePacketAcked(ProbeCluster{ID: 3, MinPackets: 5}, 1000, start, start.Add(1200*time.Millisecond))
+	assert.Len(t, e.clusters, 2)
+	assert.NotContains(t, e.clusters, 1)
+}
@@ -0,0 +1,182 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+)
+
+const (
+	// firstInitialProbeFactor and secondInitialProbeFactor are the factors
+	// the initial bitrate is multiplied with to get the bitrates of the probes
+	// sent at startup.
+	firstInitialProbeFactor  = 3
+	secondInitialProbeFactor = 6
+
+	// probeClusterDuration is the time over which the packets of a probe
+	// cluster are spread.
+	probeClusterDuration = 15 * time.Millisecond
+	// minProbePackets is the smallest number of packets in a probe cluster.
+	minProbePackets = 5
+
+	// furtherProbeThreshold is the fraction of the probed bitrate a probe
+	// result must exceed to probe again at a higher bitrate.
+	furtherProbeThreshold = 0.7
+	// furtherProbeFactor is the factor the probe result is multiplied with
+	// to get the bitrate of the next probe.
+	furtherProbeFactor = 2.0
+	// maxWaitForProbeResult is the time after which no result is expected
+	// anymore for the last probe.
+	maxWaitForProbeResult = time.Second
+
+	// alrProbeInterval is the interval between probes while the application
+	// does not use the full target bitrate.
+	alrProbeInterval = 5 * time.Second
+	// alrProbeFactor is the factor the target bitrate is multiplied with to
+	// get the bitrate of probes sent while application limited.
+	alrProbeFactor = 2.0
+
+	// maxBitrateProbeThreshold is the fraction of the previous max bitrate
+	// the target bitrate must exceed to probe the new max bitrate after the
+	// max bitrate was increased.
+	maxBitrateProbeThreshold = 0.9
+)
+
+// ProbeCluster describes a burst of packets which is sent at Bitrate to find
+// out whether the path supports it. A cluster is complete once at least
+// MinPackets packets and MinBytes bytes were sent. Packets sent as part of a
+// cluster are padding if no media is available.
+type ProbeCluster struct {
+	ID         int
+	Bitrate    int
+	MinPackets int
+	MinBytes   int
+}
+
+type probeControllerState int
+
+const (
+	probeControllerStateInit probeControllerState = iota
+	probeControllerStateWaitingForResult
+	probeControllerStateDone
+)
+
+// probeController decides when to probe and at which bitrates. It probes at
+// multiples of the initial bitrate at startup and keeps probing at twice the
+// result as long as the results come close to the probed bitrate. It probes
+// the new max bitrate when the target bitrate was limited by the old one, and
+// periodically while the application does not use the full target bitrate.
+type probeController struct {
+	maxBitrate int
+
+	state                    probeControllerState
+	nextClusterID            int
+	lastProbe                time.Time
+	minBitrateToProbeFurther int
+	pending                  []int
+
+	applicationLimited      bool
+	applicationLimitedStart time.Time
+}
+
+func newProbeController(maxBitrate int) *probeController {
+	return &probeController{
+		maxBitrate:               maxBitrate,
+		state:                    probeControllerStateInit,
+		nextClusterID:            0,
+		lastProbe:                time.Time{},
+		minBitrateToProbeFurther: 0,
+		pending:                  nil,
+		applicationLimited:       false,
+		applicationLimitedStart:  time.Time{},
+	}
+}
+
+// process returns the probe clusters that should be sent at now. bitrate is
+// the current target bitrate.
+func (p *probeController) process(now time.Time, bitrate int) []ProbeCluster {
+	switch p.state {
+	case probeControllerStateInit:
+		p.state = probeControllerStateWaitingForResult
+		p.pending = append(p.pending, firstInitialProbeFactor*bitrate, secondInitialProbeFactor*bitrate)
+	case probeControllerStateWaitingForResult:
+		if len(p.pending) == 0 && now.Sub(p.lastProbe) > maxWaitForProbeResult {
+			p.state = probeControllerStateDone
+		}
+	case probeControllerStateDone:
+	}
+	if p.state == probeControllerStateDone && p.applicationLimited &&
+		now.Sub(p.applicationLimitedStart) >= alrProbeInterval && now.Sub(p.lastProbe) >= alrProbeInterval {
+		p.state = probeControllerStateWaitingForResult
+		p.pending = append(p.pending, int(alrProbeFactor*float64(bitrate)))
+	}
+
+	return p.probe(now)
+}
+
+// probe returns clusters for all pending probes.
+func (p *probeController) probe(now time.Time) []ProbeCluster {
+	if len(p.pending) == 0 {
+		return nil
+	}
+	clusters := make([]ProbeCluster, 0, len(p.pending))
+	for _, bitrate := range p.pending {
+		bitrate = min(bitrate, p.maxBitrate)
+		if len(clusters) > 0 && clusters[len(clusters)-1].Bitrate >= bitrate {
+			// All further probes would be capped at the max bitrate.
+			break
+		}
+		clusters = append(clusters, ProbeCluster{
+			ID:         p.nextClusterID,
+			Bitrate:    bitrate,
+			MinPackets: minProbePackets,
+			MinBytes:   int(float64(bitrate) * probeClusterDuration.Seconds() / 8),
+		})
+		p.nextClusterID++
+		p.minBitrateToProbeFurther = int(furtherProbeThreshold * float64(bitrate))
+	}
+	if clusters[len(clusters)-1].Bitrate >= p.maxBitrate {
+		p.minBitrateToProbeFurther = p.maxBitrate
+	}
+	p.pending = p.pending[:0]
+	p.lastProbe = now
+
+	return clusters
+}
+
+// onProbeResult schedules a further probe if bitrate is close to the bitrate
+// of the last probe.
+func (p *probeController) onProbeResult(bitrate int) {
+	if p.state != probeControllerStateWaitingForResult {
+		return
+	}
+	if bitrate > p.minBitrateToProbeFurther && bitrate < p.maxBitrate {
+		p.pending = append(p.pending, int(furtherProbeFactor*float64(bitrate)))
+		// Later results of the same cluster must not schedule more probes.
+		p.minBitrateToProbeFurther = p.maxBitrate
+	}
+}
+
+// setMaxBitrate probes the new max bitrate if bitrate was limited by the old
+// one.
+func (p *probeController) setMaxBitrate(maxBitrate, bitrate int) {
+	previous := p.maxBitrate
+	p.maxBitrate = maxBitrate
+	if p.state == probeControllerStateInit || maxBitrate <= previous {
+		return
+	}
+	if float64(bitrate) >= maxBitrateProbeThreshold*float64(previous) {
+		p.state = probeControllerStateWaitingForResult
+		p.pending = append(p.pending, maxBitrate)
+	}
+}
+
+// setApplicationLimited records whether the application currently uses less
+// than the target bitrate.
+func (p *probeController) setApplicationLimited(now time.Time, limited bool) {
+	if limited && !p.applicationLimited {
+		p.applicationLimitedStart = now
+	}
+	p.applicationLimited = limited
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func probeBitrates(clusters []ProbeCluster) []int {
+	bitrates := []int{}
+	for _, c := range clusters {
+		bitrates = append(bitrates, c.Bitrate)
+	}
+
+	return bitrates
+}
+
+func TestProbeControllerInitialProbes(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newProbeController(10_000_000)
+	clusters := p.process(start, 1_000_000)
+	assert.Equal(t, []ProbeCluster{
+		{ID: 0, Bitrate: 3_000_000, MinPackets: minProbePackets, MinBytes: 5625},
+		{ID: 1, Bitrate: 6_000_000, MinPackets: minProbePackets, MinBytes: 11250},
+	}, clusters)
+	assert.Empty(t, p.process(start.Add(10*time.Millisecond), 1_000_000))
+}
+
+func TestProbeControllerInitialProbesCappedAtMaxBitrate(t *testing.T) {
+	p := newProbeController(4_000_000)
+	assert.Equal(t, []int{3_000_000, 4_000_000}, probeBitrates(p.process(time.Time{}.Add(time.Second), 1_000_000)))
+
+	// The max bitrate was probed, so there is nothing to probe further.
+	p.onProbeResult(3_900_000)
+	assert.Empty(t, p.process(time.Time{}.Add(2*time.Second), 3_900_000))
+
+	p = newProbeController(2_000_000)
+	assert.Equal(t, []int{2_000_000}, probeBitrates(p.process(time.Time{}.Add(time.Second), 1_000_000)))
+}
+
+func TestProbeControllerFurtherProbes(t *testing.T) {
+	cases := []struct {
+		name       string
+		maxBitrate int
+		result     int
+		expected   []int
+	}{
+		{name: "closeToProbedBitrate", maxBitrate: 20_000_000, result: 5_000_000, expected: []int{10_000_000}},
+		{name: "cappedAtMaxBitrate", maxBitrate: 8_000_000, result: 5_000_000, expected: []int{8_000_000}},
+		{name: "belowProbedBitrate", maxBitrate: 20_000_000, result: 4_000_000, expected: []int{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Time{}.Add(time.Second)
+			p := newProbeController(tc.maxBitrate)
+			p.process(start, 1_000_000)
+			p.onProbeResult(tc.result)
+			// Later results of the same cluster don't trigger more probes.
+			p.onProbeResult(tc.result)
+			assert.Equal(t, tc.expected, probeBitrates(p.process(start.Add(50*time.Millisecond), tc.result)))
+		})
+	}
+}
+
+func TestProbeControllerStopsWaitingForResult(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newProbeController(20_000_000)
+	p.process(start, 1_000_000)
+	assert.Empty(t, p.process(start.Add(2*time.Second), 1_000_000))
+	assert.Equal(t, probeControllerStateDone, p.state)
+
+	p.onProbeResult(5_900_000)
+	assert.Empty(t, p.process(start.Add(3*time.Second), 5_900_000))
+}
+
+func TestProbeControllerProbesWhileApplicationLimited(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	p := newProbeController(20_000_000)
+	p.process(start, 1_000_000)
+	p.process(start.Add(2*time.Second), 1_000_000)
+
+	p.setApplicationLimited(start.Add(2*time.Second), true)
+	assert.Empty(t, p.process(start.Add(6*time.Second), 1_000_000))
+	assert.Equal(t, []int{2_000_000}, probeBitrates(p.process(start.Add(7*time.Second), 1_000_000)))
+	assert.Empty(t, p.process(start.Add(9*time.Second), 1_000_000))
+	assert.Equal(t, []int{2_000_000}, probeBitrates(p.process(start.Add(12*time.Second), 1_000_000)))
+
+	p.setApplicationLimited(start.Add(12*time.Second), false)
+	assert.Empty(t, p.process(start.Add(20*time.Second), 1_000_000))
+}
+
+func TestProbeControllerProbesNewMaxBitrate(t *testing.T) {
+	cases := []struct {
+		name       string
+		bitrate    int
+		maxBitrate int
+		expected   []int
+	}{
+		{name: "limitedByMaxBitrate", bitrate: 950_000, maxBitrate: 2_000_000, expected: []int{2_000_000}},
+		{name: "notLimitedByMaxBitrate", bitrate: 500_000, maxBitrate: 2_000_000, expected: []int{}},
+		{name: "maxBitrateDecreased", bitrate: 950_000, maxBitrate: 900_000, expected: []int{}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start := time.Time{}.Add(time.Second)
+			p := newProbeController(1_000_000)
+			p.process(start, 300_000)
+			p.process(start.Add(2*time.Second), tc.bitrate)
+			p.setMaxBitrate(tc.maxBitrate, tc.bitrate)
+			assert.Equal(t, tc.expected, probeBitrates(p.process(start.Add(3*time.Second), tc.bitrate)))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	defaultRTT = 100 * time.Millisecond
+
+	// beta is the factor the delivery rate is multiplied with on decrease.
+	beta = 0.85
+
+	// multiplicativeIncreaseFactor is the factor the bitrate is multiplied
+	// with per second of multiplicative increase.
+	multiplicativeIncreaseFactor = 1.08
+
+	// minIncrease is the smallest increase in bits per second per update
+	// during multiplicative increase and per second during additive increase.
+	minIncrease = 1000
+
+	// averageMaxBitrateAlpha is the smoothing factor of the moving average of
+	// the delivery rate measured in the decrease state.
+	averageMaxBitrateAlpha = 0.05
+
+	// minAverageMaxBitrateStdDev is the lower bound of the standard deviation
+	// of the average max bitrate relative to the average. Without it, the
+	// neighborhood of the average would be empty after the first decrease.
+	minAverageMaxBitrateStdDev = 0.02
+
+	// assumedFPS and assumedPacketSize are used to estimate the size of a
+	// frame for additive increase.
+	assumedFPS        = 30
+	assumedPacketSize = 1200
+)
+
+// rateController applies the state transitions of the delay-based controller
+// to a bitrate as described in section 5.5 of draft-ietf-rmcat-gcc-02. It
+// increases multiplicatively while the bitrate is far from the average max
+// bitrate, which is the delivery rate measured during previous decreases, and
+// additively when close to it.
+type rateController struct {
+	minBitrate int
+	maxBitrate int
+
+	bitrate    float64
+	lastUpdate time.Time
+	rtt        time.Duration
+
+	averageMaxBitrate *ewma
+}
+
+func newRateController(initialBitrate, minBitrate, maxBitrate int) *rateController {
+	return &rateController{
+		minBitrate:        minBitrate,
+		maxBitrate:        maxBitrate,
+		bitrate:           float64(initialBitrate),
+		lastUpdate:        time.Time{},
+		rtt:               defaultRTT,
+		averageMaxBitrate: newEWMA(averageMaxBitrateAlpha),
+	}
+}
+
+func (c *rateController) onRTT(rtt time.Duration) {
+	c.rtt = rtt
+}
+
+// update applies s to the current bitrate and returns the new bitrate.
+// deliveryRate is the most recent measurement of the rate at which packets
+// arrived at the receiver and confidence the confidence in the measurement
+// between 0 and 1. A deliveryRate of 0 means no measurement is available.
+func (c *rateController) update(now time.Time, s state, deliveryRate int, confidence float64) int {
+	elapsed := time.Duration(0)
+	if !c.lastUpdate.IsZero() {
+		elapsed = now.Sub(c.lastUpdate)
+	}
+	c.lastUpdate = now
+
+	switch s {
+	case stateIncrease:
+		c.increase(elapsed, float64(deliveryRate))
+	case stateDecrease:
+		c.decrease(float64(deliveryRate), confidence)
+	case stateHold:
+	}
+	c.bitrate = max(float64(c.minBitrate), min(c.bitrate, float64(c.maxBitrate)))
+
+	return int(c.bitrate)
+}
+
+func (c *rateController) increase(elapsed time.Duration, deliveryRate float64) {
+	if c.averageMaxBitrate.initialized && deliveryRate > c.averageMaxBitrate.avg()+3*c.averageMaxBitrateStdDev() {
+		// The delivery rate is well above anything measured during previous
+		// decreases, so the congestion level has changed and the average no
+		// longer describes the link.
+		c.averageMaxBitrate = newEWMA(averageMaxBitrateAlpha)
+	}
+
+	var increase float64
+	if c.nearConvergence(deliveryRate) {
+		increase = c.additiveIncrease(elapsed)
+	} else {
+		alpha := math.Pow(multiplicativeIncreaseFactor, min(elapsed.Seconds(), 1))
+		increase = max(c.bitrate*(alpha-1), minIncrease)
+	}
+	next := c.bitrate + increase
+
+	if deliveryRate > 0 {
+		// Don't let the bitrate grow too far beyond what the link has been
+		// shown to deliver. If the bitrate is above the limit already, e.g.
+		// because the application does not use the full bitrate, keep it.
+		limit := 1.5*deliveryRate + 10_000
+		if next > limit {
+			next = max(limit, c.bitrate)
+		}
+	}
+	c.bitrate = next
+}
+
+func (c *rateController) decrease(deliveryRate, confidence float64) {
+	// Without a delivery rate measurement there is nothing to back off to, so
+	// keep the current bitrate until one is available.
+	if deliveryRate <= 0 {
+		return
+	}
+	if c.averageMaxBitrate.initialized && deliveryRate < c.averageMaxBitrate.avg()-3*c.averageMaxBitrateStdDev() {
+		c.averageMaxBitrate = newEWMA(averageMaxBitrateAlpha)
+	}
+	c.averageMaxBitrate.update(deliveryRate)
+
+	// An uncertain measurement may be far below the actual delivery rate, so
+	// back off less the less confident it is.
+	backoffRate := confidence*deliveryRate + (1-confidence)*c.bitrate
+	c.bitrate = min(c.bitrate, beta*backoffRate)
+}
+
+func (c *rateController) nearConvergence(deliveryRate float64) bool {
+	if !c.averageMaxBitrate.initialized || deliveryRate <= 0 {
+		return false
+	}
+
+	return math.Abs(deliveryRate-c.averageMaxBitrate.avg()) <= 3*c.averageMaxBitrateStdDev()
+}
+
+func (c *rateController) averageMaxBitrateStdDev() float64 {
+	return max(math.Sqrt(c.averageMaxBitrate.varr()), minAverageMaxBitrateStdDev*c.averageMaxBitrate.avg())
+}
+
+// additiveIncrease returns the increase for elapsed, which is roughly one
+// packet per response time.
+func (c *rateController) additiveIncrease(elapsed time.Duration) float64 {
+	frameSize := c.bitrate / (8 * assumedFPS)
+	packetsPerFrame := math.Ceil(frameSize / assumedPacketSize)
+	packetSize := frameSize / packetsPerFrame
+	responseTime := c.rtt + 100*time.Millisecond
+	increasePerSecond := max(8*packetSize/responseTime.Seconds(), minIncrease)
+
+	return increasePerSecond * elapsed.Seconds()
+}
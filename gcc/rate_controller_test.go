@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateController(t *testing.T) {
+	type step struct {
+		offset       time.Duration
+		state        state
+		deliveryRate int
+		expected     int
+	}
+	cases := []struct {
+		name           string
+		init, min, max int
+		steps          []step
+		expectAverage  bool
+	}{
+		{
+			name: "multiplicativeIncrease",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateIncrease, deliveryRate: 1_000_000, expected: 1_001_000},
+				{offset: time.Second, state: stateIncrease, deliveryRate: 1_000_000, expected: 1_081_080},
+			},
+		},
+		{
+			name: "increaseLimitedByDeliveryRate",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateIncrease, deliveryRate: 100_000, expected: 1_000_000},
+				{offset: time.Second, state: stateIncrease, deliveryRate: 700_000, expected: 1_060_000},
+			},
+		},
+		{
+			name: "hold",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateHold, deliveryRate: 1_000_000, expected: 1_000_000},
+				{offset: time.Second, state: stateHold, deliveryRate: 2_000_000, expected: 1_000_000},
+			},
+		},
+		{
+			name: "decrease",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 800_000, expected: 680_000},
+			},
+			expectAverage: true,
+		},
+		{
+			name: "decreaseWithoutDeliveryRate",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 0, expected: 1_000_000},
+			},
+		},
+		{
+			name: "decreaseNeverIncreases",
+			init: 500_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 800_000, expected: 500_000},
+			},
+			expectAverage: true,
+		},
+		{
+			name: "clampsToMin",
+			init: 500_000, min: 100_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 10_000, expected: 100_000},
+			},
+			expectAverage: true,
+		},
+		{
+			name: "clampsToMax",
+			init: 1_000_000, min: 10_000, max: 1_050_000,
+			steps: []step{
+				{offset: 0, state: stateIncrease, deliveryRate: 1_000_000, expected: 1_001_000},
+				{offset: time.Second, state: stateIncrease, deliveryRate: 1_000_000, expected: 1_050_000},
+			},
+		},
+		{
+			name: "additiveIncreaseNearAverageMaxBitrate",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 1_000_000, expected: 850_000},
+				// Three packets per frame of 1180.56 bytes each, one packet per
+				// response time of 200ms.
+				{offset: time.Second, state: stateIncrease, deliveryRate: 1_000_000, expected: 897_222},
+			},
+			expectAverage: true,
+		},
+		{
+			name: "resetsAverageMaxBitrateFarAboveAverage",
+			init: 1_000_000, min: 10_000, max: 10_000_000,
+			steps: []step{
+				{offset: 0, state: stateDecrease, deliveryRate: 1_000_000, expected: 850_000},
+				{offset: time.Second, state: stateIncrease, deliveryRate: 1_200_000, expected: 918_000},
+			},
+			expectAverage: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := newRateController(tc.init, tc.min, tc.max)
+			start := time.Time{}.Add(time.Second)
+			for _, s := range tc.steps {
+				assert.Equal(t, s.expected, rc.update(start.Add(s.offset), s.state, s.deliveryRate, 1))
+			}
+			assert.Equal(t, tc.expectAverage, rc.averageMaxBitrate.initialized)
+		})
+	}
+}
+
+func TestRateControllerAdditiveIncreaseDependsOnRTT(t *testing.T) {
+	increase := func(rtt time.Duration) int {
+		rc := newRateController(1_000_000, 10_000, 10_000_000)
+		rc.onRTT(rtt)
+		start := time.Time{}.Add(time.Second)
+		decreased := rc.update(start, stateDecrease, 1_000_000, 1)
+
+		return rc.update(start.Add(time.Second), stateIncrease, 1_000_000, 1) - decreased
+	}
+	assert.Greater(t, increase(50*time.Millisecond), increase(500*time.Millisecond))
+}
+
+func TestRateControllerDecreaseDependsOnConfidence(t *testing.T) {
+	cases := []struct {
+		name       string
+		confidence float64
+		expected   int
+	}{
+		{name: "confident", confidence: 1, expected: 425_000},
+		{name: "halfConfident", confidence: 0.5, expected: 637_500},
+		{name: "notConfident", confidence: 0, expected: 850_000},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rc := newRateController(1_000_000, 10_000, 10_000_000)
+			assert.Equal(t, tc.expected, rc.update(time.Time{}.Add(time.Second), stateDecrease, 500_000, tc.confidence))
+		})
+	}
+}
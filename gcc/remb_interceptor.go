@@ -0,0 +1,324 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/logging"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+)
+
+const (
+	absSendTimeURI = "http://www.webrtc.org/experiments/rtp-hdrext/abs-send-time"
+
+	defaultREMBInterval = 200 * time.Millisecond
+
+	// rembUpdateInterval is the interval at which the estimate is updated
+	// from the incoming packets.
+	rembUpdateInterval = 100 * time.Millisecond
+
+	// rembDecreaseThreshold is the fraction by which the estimate must drop
+	// below the last reported estimate to be reported immediately.
+	rembDecreaseThreshold = 0.03
+)
+
+var errInvalidREMBOption = errors.New("invalid REMB option")
+
+// REMBInterceptorOption can be used to set initial options on REMB
+// interceptors.
+type REMBInterceptorOption func(*REMBInterceptorFactory) error
+
+// REMBLoggerFactory sets the logger factory used by the interceptor.
+func REMBLoggerFactory(lf logging.LoggerFactory) REMBInterceptorOption {
+	return func(f *REMBInterceptorFactory) error {
+		f.loggerFactory = lf
+
+		return nil
+	}
+}
+
+// REMBControllerOptions sets the options used to create the Controller of
+// each interceptor.
+func REMBControllerOptions(opts ...Option) REMBInterceptorOption {
+	return func(f *REMBInterceptorFactory) error {
+		f.controllerOptions = append(f.controllerOptions, opts...)
+
+		return nil
+	}
+}
+
+// REMBInterval sets the interval at which the estimate is sent. Decreases of
+// the estimate are sent immediately. The default is 200ms.
+func REMBInterval(interval time.Duration) REMBInterceptorOption {
+	return func(f *REMBInterceptorFactory) error {
+		if interval <= 0 {
+			return fmt.Errorf("%w: interval %v must be positive", errInvalidREMBOption, interval)
+		}
+		f.interval = interval
+
+		return nil
+	}
+}
+
+func rembTimeFactory(timestamp func() time.Time) REMBInterceptorOption {
+	return func(f *REMBInterceptorFactory) error {
+		f.timestamp = timestamp
+
+		return nil
+	}
+}
+
+// REMBInterceptorFactory is a factory for REMB interceptors.
+type REMBInterceptorFactory struct {
+	loggerFactory     logging.LoggerFactory
+	controllerOptions []Option
+	interval          time.Duration
+	timestamp         func() time.Time
+}
+
+// NewREMBInterceptor returns a new REMB InterceptorFactory. Interceptors
+// created by the factory run the delay-based estimation of GCC on the
+// receiver and report the estimate to the sender in REMB packets. This is
+// meant for senders which do not support TWCC or RFC 8888 feedback.
+func NewREMBInterceptor(opts ...REMBInterceptorOption) (*REMBInterceptorFactory, error) {
+	factory := &REMBInterceptorFactory{
+		loggerFactory:     logging.NewDefaultLoggerFactory(),
+		controllerOptions: []Option{},
+		interval:          defaultREMBInterval,
+		timestamp:         time.Now,
+	}
+	for _, opt := range opts {
+		if err := opt(factory); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := NewController(factory.controllerOptions...); err != nil {
+		return nil, err
+	}
+
+	return factory, nil
+}
+
+// NewInterceptor returns a new interceptor estimating the bitrate of all
+// remote streams.
+func (f *REMBInterceptorFactory) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	return f.newREMBInterceptor()
+}
+
+func (f *REMBInterceptorFactory) newREMBInterceptor() (*REMBInterceptor, error) {
+	controller, err := NewController(append(f.controllerOptions, WithProbing(false))...)
+	if err != nil {
+		return nil, err
+	}
+	rembInterceptor := &REMBInterceptor{
+		NoOp:                       interceptor.NoOp{},
+		log:                        f.loggerFactory.NewLogger("gcc_remb_interceptor"),
+		timestamp:                  f.timestamp,
+		interval:                   f.interval,
+		senderSSRC:                 rand.Uint32(), // nolint:gosec
+		lock:                       sync.Mutex{},
+		controller:                 controller,
+		absSendTimeUnwrapper:       newAbsSendTimeUnwrapper(),
+		transportSequenceUnwrapper: newUnwrapper(16),
+		nextSequenceNumber:         0,
+		ssrcs:                      nil,
+		lastUpdate:                 time.Time{},
+		lastREMB:                   0,
+		writer:                     nil,
+		wg:                         sync.WaitGroup{},
+		close:                      make(chan struct{}),
+	}
+	rembInterceptor.wg.Add(1)
+	go rembInterceptor.run()
+
+	return rembInterceptor, nil
+}
+
+// REMBInterceptor runs a Controller on the packets received on all remote
+// streams and sends the estimate to the sender in REMB packets. The send time
+// of a packet is taken from the abs-send-time header extension, so packets
+// without the extension are ignored. If the transport wide sequence number
+// extension is negotiated as well, it orders the packets of all streams,
+// otherwise they are ordered by arrival.
+type REMBInterceptor struct {
+	interceptor.NoOp
+	log        logging.LeveledLogger
+	timestamp  func() time.Time
+	interval   time.Duration
+	senderSSRC uint32
+
+	lock                       sync.Mutex
+	controller                 *Controller
+	absSendTimeUnwrapper       *absSendTimeUnwrapper
+	transportSequenceUnwrapper *unwrapper
+	nextSequenceNumber         uint64
+	ssrcs                      []uint32
+	lastUpdate                 time.Time
+	lastREMB                   int
+	writer                     interceptor.RTCPWriter
+
+	wg    sync.WaitGroup
+	close chan struct{}
+}
+
+// TargetBitrate returns the current estimate in bits per second.
+func (i *REMBInterceptor) TargetBitrate() int {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.controller.TargetBitrate()
+}
+
+// BindRTCPWriter implements interceptor.Interceptor.
+func (i *REMBInterceptor) BindRTCPWriter(writer interceptor.RTCPWriter) interceptor.RTCPWriter {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.writer = writer
+
+	return writer
+}
+
+// BindRemoteStream implements interceptor.Interceptor.
+func (i *REMBInterceptor) BindRemoteStream(
+	info *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	var absSendTimeHdrExtID, twccHdrExtID uint8
+	for _, e := range info.RTPHeaderExtensions {
+		switch e.URI {
+		case absSendTimeURI:
+			absSendTimeHdrExtID = uint8(e.ID) // nolint:gosec
+		case transportCCURI:
+			twccHdrExtID = uint8(e.ID) // nolint:gosec
+		}
+	}
+	if absSendTimeHdrExtID == 0 {
+		return reader
+	}
+	i.lock.Lock()
+	i.ssrcs = append(i.ssrcs, info.SSRC)
+	i.lock.Unlock()
+
+	return interceptor.RTPReaderFunc(func(b []byte, a interceptor.Attributes) (int, interceptor.Attributes, error) {
+		n, attr, err := reader.Read(b, a)
+		if err != nil {
+			return n, attr, err
+		}
+		if attr == nil {
+			attr = make(interceptor.Attributes)
+		}
+		header, err := attr.GetRTPHeader(b[:n])
+		if err != nil {
+			return n, attr, err
+		}
+		absSendTime, err := parseAbsSendTime(header.GetExtension(absSendTimeHdrExtID))
+		if err != nil {
+			i.log.Debugf("ignoring packet: %v", err)
+
+			return n, attr, nil
+		}
+		var twcc rtp.TransportCCExtension
+		hasTWCC := twccHdrExtID != 0 && twcc.Unmarshal(header.GetExtension(twccHdrExtID)) == nil
+		i.onPacket(absSendTime, hasTWCC, twcc.TransportSequence, n)
+
+		return n, attr, nil
+	})
+}
+
+// UnbindRemoteStream implements interceptor.Interceptor.
+func (i *REMBInterceptor) UnbindRemoteStream(info *interceptor.StreamInfo) {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	for j, ssrc := range i.ssrcs {
+		if ssrc == info.SSRC {
+			i.ssrcs = append(i.ssrcs[:j], i.ssrcs[j+1:]...)
+
+			break
+		}
+	}
+}
+
+func (i *REMBInterceptor) onPacket(absSendTime uint32, hasTWCC bool, transportSequenceNumber uint16, size int) {
+	now := i.timestamp()
+
+	i.lock.Lock()
+	sequenceNumber := i.nextSequenceNumber
+	if hasTWCC {
+		sequenceNumber = uint64(i.transportSequenceUnwrapper.unwrap(uint64(transportSequenceNumber))) // nolint:gosec
+	}
+	i.nextSequenceNumber++
+	departure := i.absSendTimeUnwrapper.departure(absSendTime)
+	i.controller.OnPacketAcked(sequenceNumber, size, departure, now)
+
+	if !i.lastUpdate.IsZero() && now.Sub(i.lastUpdate) < rembUpdateInterval {
+		i.lock.Unlock()
+
+		return
+	}
+	i.lastUpdate = now
+	target := i.controller.Update(now)
+	decreased := i.lastREMB > 0 && float64(target) < (1-rembDecreaseThreshold)*float64(i.lastREMB)
+	i.lock.Unlock()
+
+	if decreased {
+		i.sendREMB()
+	}
+}
+
+func (i *REMBInterceptor) run() {
+	defer i.wg.Done()
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-i.close:
+			return
+		case <-ticker.C:
+			i.sendREMB()
+		}
+	}
+}
+
+// sendREMB sends the current estimate for all remote streams, once packets
+// were received.
+func (i *REMBInterceptor) sendREMB() {
+	i.lock.Lock()
+	if i.writer == nil || len(i.ssrcs) == 0 || i.lastUpdate.IsZero() {
+		i.lock.Unlock()
+
+		return
+	}
+	target := i.controller.TargetBitrate()
+	i.lastREMB = target
+	writer := i.writer
+	remb := &rtcp.ReceiverEstimatedMaximumBitrate{
+		SenderSSRC: i.senderSSRC,
+		Bitrate:    float32(target),
+		SSRCs:      append([]uint32(nil), i.ssrcs...),
+	}
+	i.lock.Unlock()
+
+	if _, err := writer.Write([]rtcp.Packet{remb}, interceptor.Attributes{}); err != nil {
+		i.log.Warnf("failed to write REMB: %v", err)
+	}
+}
+
+// Close stops sending REMB packets.
+func (i *REMBInterceptor) Close() error {
+	select {
+	case <-i.close:
+	default:
+		close(i.close)
+	}
+	i.wg.Wait()
+
+	return nil
+}
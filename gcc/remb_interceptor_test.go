@@ -0,0 +1,165 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtcp"
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewREMBInterceptor(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		f, err := NewREMBInterceptor(REMBInterval(time.Second), REMBControllerOptions(WithInitialBitrate(500_000)))
+		assert.NoError(t, err)
+		i, err := f.NewInterceptor("")
+		assert.NoError(t, err)
+		assert.NoError(t, i.Close())
+	})
+	t.Run("invalidInterval", func(t *testing.T) {
+		f, err := NewREMBInterceptor(REMBInterval(0))
+		assert.ErrorIs(t, err, errInvalidREMBOption)
+		assert.Nil(t, f)
+	})
+	t.Run("invalidControllerOptions", func(t *testing.T) {
+		f, err := NewREMBInterceptor(REMBControllerOptions(WithMinBitrate(-1)))
+		assert.ErrorIs(t, err, errInvalidBitrate)
+		assert.Nil(t, f)
+	})
+}
+
+// rembRecorder records the REMB packets written by an interceptor.
+type rembRecorder struct {
+	lock  sync.Mutex
+	rembs []*rtcp.ReceiverEstimatedMaximumBitrate
+}
+
+func (r *rembRecorder) Write(pkts []rtcp.Packet, _ interceptor.Attributes) (int, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, pkt := range pkts {
+		if remb, ok := pkt.(*rtcp.ReceiverEstimatedMaximumBitrate); ok {
+			r.rembs = append(r.rembs, remb)
+		}
+	}
+
+	return 0, nil
+}
+
+func (r *rembRecorder) get() []*rtcp.ReceiverEstimatedMaximumBitrate {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]*rtcp.ReceiverEstimatedMaximumBitrate(nil), r.rembs...)
+}
+
+func TestREMBInterceptor(t *testing.T) {
+	var lock sync.Mutex
+	now := time.Unix(0, 0)
+	f, err := NewREMBInterceptor(
+		REMBInterval(time.Hour),
+		REMBControllerOptions(WithInitialBitrate(3_000_000)),
+		rembTimeFactory(func() time.Time {
+			lock.Lock()
+			defer lock.Unlock()
+
+			return now
+		}),
+	)
+	assert.NoError(t, err)
+	i, err := f.newREMBInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	recorder := &rembRecorder{}
+	i.BindRTCPWriter(recorder)
+
+	var packet []byte
+	reader := i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC: 1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+			{URI: absSendTimeURI, ID: 3},
+		},
+	}, interceptor.RTPReaderFunc(func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+		return copy(b, packet), nil, nil
+	}))
+	// A stream without abs-send-time is not reported.
+	i.BindRemoteStream(&interceptor.StreamInfo{SSRC: 2}, nil)
+
+	start := now
+	// 1200 bytes every 5ms is a 1.92 Mbps stream, below the initial bitrate.
+	// After two seconds, the queueing delay grows by 1ms per packet, which
+	// decreases the estimate to below the delivery rate.
+	for seq := 0; seq < 600; seq++ {
+		departure := start.Add(time.Duration(seq) * 5 * time.Millisecond)
+		queueing := time.Duration(max(0, seq-400)) * time.Millisecond
+		ext, err := rtp.NewAbsSendTimeExtension(departure).Marshal()
+		assert.NoError(t, err)
+		header := rtp.Header{Version: 2, SSRC: 1, SequenceNumber: uint16(seq)} // nolint:gosec
+		assert.NoError(t, header.SetExtension(3, ext))
+		packet, err = (&rtp.Packet{Header: header, Payload: make([]byte, 1200)}).Marshal()
+		assert.NoError(t, err)
+
+		lock.Lock()
+		now = departure.Add(20*time.Millisecond + queueing)
+		lock.Unlock()
+		_, _, err = reader.Read(make([]byte, 1500), nil)
+		assert.NoError(t, err)
+
+		if seq == 399 {
+			// Nothing was reported yet, so the first report is periodic.
+			assert.Empty(t, recorder.get())
+			i.sendREMB()
+			rembs := recorder.get()
+			assert.Len(t, rembs, 1)
+			assert.Equal(t, []uint32{1}, rembs[0].SSRCs)
+			assert.Equal(t, float32(i.TargetBitrate()), rembs[0].Bitrate)
+		}
+	}
+
+	// The decrease was reported without waiting for the interval.
+	rembs := recorder.get()
+	assert.Greater(t, len(rembs), 1)
+	assert.Less(t, rembs[len(rembs)-1].Bitrate, rembs[0].Bitrate)
+}
+
+func TestREMBInterceptorInterval(t *testing.T) {
+	f, err := NewREMBInterceptor(REMBInterval(5 * time.Millisecond))
+	assert.NoError(t, err)
+	i, err := f.newREMBInterceptor()
+	assert.NoError(t, err)
+	defer func() {
+		assert.NoError(t, i.Close())
+	}()
+
+	recorder := &rembRecorder{}
+	i.BindRTCPWriter(recorder)
+	header := rtp.Header{Version: 2, SSRC: 1}
+	ext, err := rtp.NewAbsSendTimeExtension(time.Now()).Marshal()
+	assert.NoError(t, err)
+	assert.NoError(t, header.SetExtension(3, ext))
+	packet, err := (&rtp.Packet{Header: header, Payload: make([]byte, 1200)}).Marshal()
+	assert.NoError(t, err)
+	reader := i.BindRemoteStream(&interceptor.StreamInfo{
+		SSRC: 1,
+		RTPHeaderExtensions: []interceptor.RTPHeaderExtension{
+			{URI: absSendTimeURI, ID: 3},
+		},
+	}, interceptor.RTPReaderFunc(func(b []byte, _ interceptor.Attributes) (int, interceptor.Attributes, error) {
+		return copy(b, packet), nil, nil
+	}))
+	_, _, err = reader.Read(make([]byte, 1500), nil)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return len(recorder.get()) >= 2
+	}, time.Second, time.Millisecond)
+}
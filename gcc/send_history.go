@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"time"
+)
+
+const (
+	// maxSentPackets is the maximum number of packets kept in the history. It
+	// is well below the range of 16 bit sequence numbers, so sequence numbers
+	// in feedback can be unwrapped unambiguously.
+	maxSentPackets = 1 << 14
+
+	// sendHistoryMaxAge is the time after which packets are evicted from the
+	// history. Feedback for older packets is ignored.
+	sendHistoryMaxAge = time.Minute
+
+	initialSendHistorySize = 1 << 10
+)
+
+type sentPacketFlags uint8
+
+const (
+	// sentPacketPadding marks packets which carry only padding.
+	sentPacketPadding sentPacketFlags = 1 << iota
+	// sentPacketRetransmission marks packets sent on a retransmission stream.
+	sentPacketRetransmission
+	// sentPacketProbe marks packets sent as part of a probe cluster.
+	sentPacketProbe
+)
+
+type feedbackStatus int
+
+const (
+	feedbackStatusNone feedbackStatus = iota
+	// feedbackStatusMissing marks packets reported lost, which may still be
+	// reported received within the reorder window.
+	feedbackStatusMissing
+	feedbackStatusLost
+	feedbackStatusReceived
+)
+
+type sentPacket struct {
+	sequenceNumber    uint64
+	ssrc              uint32
+	rtpSequenceNumber int64
+	hasTWCC           bool
+	size              int
+	departure         time.Time
+	flags             sentPacketFlags
+	probeClusterID    int
+	status            feedbackStatus
+}
+
+// sendHistory stores the packets sent most recently, keyed by the transport
+// wide sequence number it assigns to them. Packets are stored by value in a
+// ring buffer indexed by the sequence number, which only grows until it
+// reaches maxSize, so adding a packet does not allocate once the history is
+// warmed up. Packets are evicted once they are older than maxAge or once the
+// history is full.
+type sendHistory struct {
+	maxAge  time.Duration
+	maxSize int
+
+	// packets is a ring buffer whose length is a power of two. The packet
+	// with sequence number s is stored at index s & (len(packets) - 1).
+	packets []sentPacket
+	// first is the sequence number of the oldest packet in the history and
+	// next the sequence number assigned to the next packet.
+	first uint64
+	next  uint64
+}
+
+// newSendHistory creates a new sendHistory. maxSize must be a power of two.
+func newSendHistory(maxAge time.Duration, maxSize int) *sendHistory {
+	return &sendHistory{
+		maxAge:  maxAge,
+		maxSize: maxSize,
+		packets: make([]sentPacket, min(initialSendHistorySize, maxSize)),
+		first:   0,
+		next:    0,
+	}
+}
+
+func (h *sendHistory) len() int {
+	return int(h.next - h.first) // nolint:gosec
+}
+
+// add assigns the next sequence number to packet, stores it and returns the
+// sequence number.
+func (h *sendHistory) add(packet sentPacket) uint64 {
+	h.evictBefore(packet.departure.Add(-h.maxAge))
+	if h.len() == len(h.packets) {
+		if len(h.packets) < h.maxSize {
+			h.grow()
+		} else {
+			h.evictFirst()
+		}
+	}
+	packet.sequenceNumber = h.next
+	h.packets[h.index(h.next)] = packet
+	h.next++
+
+	return packet.sequenceNumber
+}
+
+// get returns the packet with sequenceNumber if it is still in the history.
+// The returned packet may be modified until the next call to add.
+func (h *sendHistory) get(sequenceNumber uint64) (*sentPacket, bool) {
+	if sequenceNumber < h.first || sequenceNumber >= h.next {
+		return nil, false
+	}
+
+	return &h.packets[h.index(sequenceNumber)], true
+}
+
+func (h *sendHistory) index(sequenceNumber uint64) int {
+	return int(sequenceNumber & uint64(len(h.packets)-1)) // nolint:gosec
+}
+
+func (h *sendHistory) grow() {
+	packets := make([]sentPacket, 2*len(h.packets))
+	for s := h.first; s < h.next; s++ {
+		packets[s&uint64(len(packets)-1)] = h.packets[h.index(s)]
+	}
+	h.packets = packets
+}
+
+// evictBefore evicts all packets which departed before deadline.
+func (h *sendHistory) evictBefore(deadline time.Time) {
+	for h.first < h.next && h.packets[h.index(h.first)].departure.Before(deadline) {
+		h.evictFirst()
+	}
+}
+
+func (h *sendHistory) evictFirst() {
+	h.packets[h.index(h.first)] = sentPacket{}
+	h.first++
+}
+
+// rtpIndex maps the unwrapped RTP sequence numbers of the packets sent on one
+// SSRC to their transport wide sequence numbers. It is a ring buffer indexed
+// by the unwrapped RTP sequence number, which is never cleaned up: Entries
+// overwritten by later packets or referring to packets evicted from the
+// history are detected by comparing the packet found in the history.
+type rtpIndex struct {
+	unwrapper       *unwrapper
+	sequenceNumbers []uint64
+}
+
+// newRTPIndex creates a new rtpIndex. size must be a power of two.
+func newRTPIndex(size int) *rtpIndex {
+	return &rtpIndex{
+		unwrapper:       newUnwrapper(16),
+		sequenceNumbers: make([]uint64, size),
+	}
+}
+
+func (r *rtpIndex) add(rtpSequenceNumber int64, sequenceNumber uint64) {
+	r.sequenceNumbers[r.index(rtpSequenceNumber)] = sequenceNumber
+}
+
+// get returns the transport wide sequence number of the last packet whose
+// unwrapped RTP sequence number maps to the same index as rtpSequenceNumber.
+func (r *rtpIndex) get(rtpSequenceNumber int64) uint64 {
+	return r.sequenceNumbers[r.index(rtpSequenceNumber)]
+}
+
+func (r *rtpIndex) index(rtpSequenceNumber int64) int {
+	return int(uint64(rtpSequenceNumber) & uint64(len(r.sequenceNumbers)-1)) // nolint:gosec
+}
@@ -0,0 +1,111 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendHistory(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	cases := []struct {
+		name      string
+		maxAge    time.Duration
+		maxSize   int
+		sent      []time.Duration
+		first     uint64
+		remaining int
+	}{
+		{
+			name:      "empty",
+			maxAge:    time.Second,
+			maxSize:   4,
+			sent:      []time.Duration{},
+			first:     0,
+			remaining: 0,
+		},
+		{
+			name:      "notFull",
+			maxAge:    time.Second,
+			maxSize:   4,
+			sent:      []time.Duration{0, time.Millisecond, 2 * time.Millisecond},
+			first:     0,
+			remaining: 3,
+		},
+		{
+			name:      "evictsWhenFull",
+			maxAge:    time.Second,
+			maxSize:   4,
+			sent:      []time.Duration{0, 1, 2, 3, 4, 5},
+			first:     2,
+			remaining: 4,
+		},
+		{
+			name:      "evictsOldPackets",
+			maxAge:    time.Second,
+			maxSize:   4,
+			sent:      []time.Duration{0, 500 * time.Millisecond, 1200 * time.Millisecond, 1600 * time.Millisecond},
+			first:     2,
+			remaining: 2,
+		},
+		{
+			name:      "grows",
+			maxAge:    time.Second,
+			maxSize:   4 * initialSendHistorySize,
+			sent:      make([]time.Duration, 3*initialSendHistorySize),
+			first:     0,
+			remaining: 3 * initialSendHistorySize,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			h := newSendHistory(tc.maxAge, tc.maxSize)
+			for i, d := range tc.sent {
+				seq := h.add(sentPacket{size: i, departure: start.Add(d)})
+				assert.Equal(t, uint64(i), seq) // nolint:gosec
+			}
+			assert.Equal(t, tc.first, h.first)
+			assert.Equal(t, tc.remaining, h.len())
+			if tc.first > 0 {
+				_, ok := h.get(tc.first - 1)
+				assert.False(t, ok)
+			}
+			for seq := tc.first; seq < uint64(len(tc.sent)); seq++ {
+				p, ok := h.get(seq)
+				if assert.True(t, ok) {
+					assert.Equal(t, seq, p.sequenceNumber)
+					assert.Equal(t, int(seq), p.size) // nolint:gosec
+					assert.Equal(t, start.Add(tc.sent[seq]), p.departure)
+				}
+			}
+			_, ok := h.get(uint64(len(tc.sent)))
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestSendHistoryModifiesInPlace(t *testing.T) {
+	h := newSendHistory(time.Second, 4)
+	seq := h.add(sentPacket{flags: sentPacketProbe | sentPacketPadding})
+	p, ok := h.get(seq)
+	assert.True(t, ok)
+	p.status = feedbackStatusReceived
+	p, ok = h.get(seq)
+	assert.True(t, ok)
+	assert.Equal(t, feedbackStatusReceived, p.status)
+	assert.Equal(t, sentPacketProbe|sentPacketPadding, p.flags)
+}
+
+func TestSendHistoryAddDoesNotAllocate(t *testing.T) {
+	h := newSendHistory(time.Minute, 8)
+	departure := time.Time{}.Add(time.Second)
+	allocs := testing.AllocsPerRun(100, func() {
+		departure = departure.Add(time.Millisecond)
+		h.add(sentPacket{size: 1200, departure: departure})
+	})
+	assert.Zero(t, allocs)
+}
@@ -4,31 +4,67 @@
 package gcc
 
 import (
+	"errors"
+	"fmt"
 	"time"
 )
 
-type trendlineEstimtatorOption func(*trendlineEstimator)
+var errInvalidDelayEstimatorOption = errors.New("invalid delay estimator option")
+
+// DelayEstimator estimates the variation of the one way delay from the delay
+// between consecutive groups of packets, i.e. the delay gradient. The overuse
+// detector compares the estimate to an adaptive threshold which starts at
+// 12.5. Only the estimate of the trendline estimator is multiplied by the
+// number of groups seen, up to 60, first.
+type DelayEstimator interface {
+	// Update adds the group which arrived at arrivalTime interGroupDelay
+	// later than expected from its departure, and returns the estimate.
+	Update(arrivalTime time.Time, interGroupDelay time.Duration) float64
+	// Reset discards all groups added so far, e.g. after the arrival times
+	// reported by the remote peer jumped.
+	Reset()
+}
+
+// TrendlineEstimatorOption configures the trendline estimator.
+type TrendlineEstimatorOption func(*trendlineEstimator)
 
-func trendlineEstimatorSmoothingCoeff(coeff float64) trendlineEstimtatorOption {
+// TrendlineSmoothingCoefficient sets the coefficient of the exponential
+// moving average applied to the accumulated delay before the line is fitted.
+// The default is 0.8.
+func TrendlineSmoothingCoefficient(coeff float64) TrendlineEstimatorOption {
 	return func(te *trendlineEstimator) {
 		te.smoothingCoeff = coeff
 	}
 }
 
-func trendlineEstimatorWindowSize(size int) trendlineEstimtatorOption {
+// TrendlineWindowSize sets the number of most recent groups the line is
+// fitted to. The default is 10.
+func TrendlineWindowSize(size int) TrendlineEstimatorOption {
 	return func(te *trendlineEstimator) {
 		te.windowSize = size
 	}
 }
 
+// TrendlineThresholdGain sets the gain the slope of the line is multiplied
+// with before it is compared to the threshold. The default is 4.
+func TrendlineThresholdGain(gain float64) TrendlineEstimatorOption {
+	return func(te *trendlineEstimator) {
+		te.thresholdGain = gain
+	}
+}
+
 type packetDelay struct {
 	arrivalTimeMS   float64
 	smoothedDelayMS float64
 }
 
+// trendlineEstimator estimates the delay gradient as the slope of a line
+// fitted to the smoothed accumulated delay of the most recent groups, like the
+// TrendlineEstimator of libwebrtc.
 type trendlineEstimator struct {
 	smoothingCoeff float64
 	windowSize     int
+	thresholdGain  float64
 
 	firstArrival     time.Time
 	accumulatedDelay time.Duration
@@ -37,10 +73,11 @@ type trendlineEstimator struct {
 	history []packetDelay
 }
 
-func newTrendlineEstimator(options ...trendlineEstimtatorOption) *trendlineEstimator {
+func newTrendlineEstimator(options ...TrendlineEstimatorOption) (*trendlineEstimator, error) {
 	te := &trendlineEstimator{
 		smoothingCoeff:   0.8,
 		windowSize:       10,
+		thresholdGain:    4,
 		firstArrival:     time.Time{},
 		accumulatedDelay: 0,
 		smoothedDelayMs:  0,
@@ -49,11 +86,27 @@ func newTrendlineEstimator(options ...trendlineEstimtatorOption) *trendlineEstim
 	for _, opt := range options {
 		opt(te)
 	}
+	if te.smoothingCoeff < 0 || te.smoothingCoeff >= 1 {
+		return nil, fmt.Errorf(
+			"%w: smoothing coefficient %v must be in [0, 1)",
+			errInvalidDelayEstimatorOption, te.smoothingCoeff,
+		)
+	}
+	if te.windowSize < 2 {
+		return nil, fmt.Errorf("%w: window size %d must be at least 2", errInvalidDelayEstimatorOption, te.windowSize)
+	}
+	if te.thresholdGain <= 0 {
+		return nil, fmt.Errorf(
+			"%w: threshold gain %v must be positive",
+			errInvalidDelayEstimatorOption, te.thresholdGain,
+		)
+	}
 
-	return te
+	return te, nil
 }
 
-func (e *trendlineEstimator) update(arrivalTime time.Time, interGroupDelay time.Duration) float64 {
+// Update implements DelayEstimator.
+func (e *trendlineEstimator) Update(arrivalTime time.Time, interGroupDelay time.Duration) float64 {
 	e.accumulatedDelay += interGroupDelay
 	e.smoothedDelayMs = e.smoothingCoeff*e.smoothedDelayMs +
 		(1-e.smoothingCoeff)*durationToMs(e.accumulatedDelay)
@@ -75,7 +128,15 @@ func (e *trendlineEstimator) update(arrivalTime time.Time, interGroupDelay time.
 		return 0
 	}
 
-	return trend
+	return trend * e.thresholdGain
+}
+
+// Reset implements DelayEstimator.
+func (e *trendlineEstimator) Reset() {
+	e.firstArrival = time.Time{}
+	e.accumulatedDelay = 0
+	e.smoothedDelayMs = 0
+	e.history = e.history[:0]
 }
 
 func fitSlope(packets []packetDelay) (float64, bool) {
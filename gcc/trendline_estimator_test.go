@@ -129,14 +129,63 @@ func TestTrendlineEstimator(t *testing.T) {
 	}
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			te := newTrendlineEstimator(
-				trendlineEstimatorSmoothingCoeff(tc.smoothingCoeff),
-				trendlineEstimatorWindowSize(tc.windowSize),
+			te, err := newTrendlineEstimator(
+				TrendlineSmoothingCoefficient(tc.smoothingCoeff),
+				TrendlineWindowSize(tc.windowSize),
+				TrendlineThresholdGain(1),
 			)
+			assert.NoError(t, err)
 			for _, v := range tc.values {
-				trend := te.update(v.arrivalTime, v.interGroupDelay)
+				trend := te.Update(v.arrivalTime, v.interGroupDelay)
 				assert.InDelta(t, v.expectedTrend, trend, 0.01)
 			}
 		})
 	}
 }
+
+func TestTrendlineEstimatorThresholdGain(t *testing.T) {
+	for _, gain := range []float64{1, 4, 10} {
+		te, err := newTrendlineEstimator(TrendlineSmoothingCoefficient(0), TrendlineThresholdGain(gain))
+		assert.NoError(t, err)
+		te.Update(time.Time{}.Add(time.Second), 0)
+		// The delay grows by 1ms per second, a slope of 0.001.
+		trend := te.Update(time.Time{}.Add(2*time.Second), time.Millisecond)
+		assert.InDelta(t, 0.001*gain, trend, 1e-9)
+	}
+}
+
+func TestTrendlineEstimatorReset(t *testing.T) {
+	te, err := newTrendlineEstimator(TrendlineSmoothingCoefficient(0), TrendlineThresholdGain(1))
+	assert.NoError(t, err)
+	arrival := time.Time{}.Add(time.Second)
+	for range 10 {
+		arrival = arrival.Add(time.Millisecond)
+		te.Update(arrival, time.Millisecond)
+	}
+	te.Reset()
+	assert.Zero(t, te.accumulatedDelay)
+	assert.Empty(t, te.history)
+	// The delay accumulated before the reset does not count towards the slope.
+	assert.InDelta(t, 0, te.Update(arrival.Add(time.Second), 0), 1e-9)
+	assert.InDelta(t, 0, te.Update(arrival.Add(time.Second+time.Millisecond), 0), 1e-9)
+	assert.InDelta(t, 1, te.Update(arrival.Add(time.Second+2*time.Millisecond), 2*time.Millisecond), 1e-9)
+}
+
+func TestTrendlineEstimatorInvalidOptions(t *testing.T) {
+	cases := []struct {
+		name    string
+		options []TrendlineEstimatorOption
+	}{
+		{name: "negativeSmoothingCoeff", options: []TrendlineEstimatorOption{TrendlineSmoothingCoefficient(-0.1)}},
+		{name: "smoothingCoeffOne", options: []TrendlineEstimatorOption{TrendlineSmoothingCoefficient(1)}},
+		{name: "windowSizeOne", options: []TrendlineEstimatorOption{TrendlineWindowSize(1)}},
+		{name: "zeroThresholdGain", options: []TrendlineEstimatorOption{TrendlineThresholdGain(0)}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			te, err := newTrendlineEstimator(tc.options...)
+			assert.ErrorIs(t, err, errInvalidDelayEstimatorOption)
+			assert.Nil(t, te)
+		})
+	}
+}
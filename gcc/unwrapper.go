@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+// unwrapAt returns the value closest to reference whose lowest bits bits are
+// equal to value.
+func unwrapAt(reference int64, value uint64, bits uint) int64 {
+	mask := uint64(1)<<bits - 1
+	// Interpret the difference of the wrapped values as a signed number of
+	// width bits, which is the shortest distance from reference to value.
+	delta := int64((value - uint64(reference)) & mask) // nolint:gosec
+	if delta >= int64(1)<<(bits-1) {
+		delta -= int64(1) << bits
+	}
+
+	return reference + delta
+}
+
+// unwrapper unwraps a sequence of values of limited width, like RTP sequence
+// numbers or RTCP timestamps, to int64 by counting how often the values
+// wrapped around. Values which are older than the previous value are unwrapped
+// relative to it, so reordering does not cause a wrap-around to be detected.
+type unwrapper struct {
+	bits        uint
+	initialized bool
+	last        int64
+}
+
+func newUnwrapper(bits uint) *unwrapper {
+	return &unwrapper{
+		bits:        bits,
+		initialized: false,
+		last:        0,
+	}
+}
+
+func (u *unwrapper) unwrap(value uint64) int64 {
+	if !u.initialized {
+		u.initialized = true
+		u.last = int64(value & (uint64(1)<<u.bits - 1)) // nolint:gosec
+
+		return u.last
+	}
+	unwrapped := unwrapAt(u.last, value, u.bits)
+	u.last = max(u.last, unwrapped)
+
+	return unwrapped
+}
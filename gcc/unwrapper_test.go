@@ -0,0 +1,77 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package gcc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnwrapAt(t *testing.T) {
+	cases := []struct {
+		name      string
+		reference int64
+		value     uint64
+		bits      uint
+		expected  int64
+	}{
+		{name: "equal", reference: 17, value: 17, bits: 16, expected: 17},
+		{name: "forward", reference: 17, value: 20, bits: 16, expected: 20},
+		{name: "backward", reference: 17, value: 15, bits: 16, expected: 15},
+		{name: "forwardWrap", reference: 65535, value: 1, bits: 16, expected: 65537},
+		{name: "backwardWrap", reference: 65537, value: 65535, bits: 16, expected: 65535},
+		{name: "negative", reference: 1, value: 65535, bits: 16, expected: -1},
+		{name: "secondWrap", reference: 2*65536 + 10, value: 5, bits: 16, expected: 2*65536 + 5},
+		{name: "24bit", reference: 1<<24 - 1, value: 3, bits: 24, expected: 1<<24 + 3},
+		{name: "32bit", reference: 1<<32 - 1, value: 0, bits: 32, expected: 1 << 32},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, unwrapAt(tc.reference, tc.value, tc.bits))
+		})
+	}
+}
+
+func TestUnwrapper(t *testing.T) {
+	cases := []struct {
+		name     string
+		values   []uint64
+		expected []int64
+	}{
+		{
+			name:     "empty",
+			values:   []uint64{},
+			expected: []int64{},
+		},
+		{
+			name:     "inOrder",
+			values:   []uint64{1, 2, 3},
+			expected: []int64{1, 2, 3},
+		},
+		{
+			name:     "wrapAround",
+			values:   []uint64{65534, 65535, 0, 1},
+			expected: []int64{65534, 65535, 65536, 65537},
+		},
+		{
+			name:     "reorderedAcrossWrapAround",
+			values:   []uint64{65535, 1, 0, 2},
+			expected: []int64{65535, 65537, 65536, 65538},
+		},
+		{
+			name:     "reorderedDoesNotMoveReference",
+			values:   []uint64{10, 40000, 9, 11},
+			expected: []int64{10, -25536, 9, 11},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			u := newUnwrapper(16)
+			for i, v := range tc.values {
+				assert.Equal(t, tc.expected[i], u.unwrap(v))
+			}
+		})
+	}
+}
@@ -3,7 +3,8 @@
 
 //go:build !js
 
-package simulation
+// Package codec implements the media sources of the simulation.
+package codec
 
 import (
 	"crypto/rand"
@@ -14,16 +15,18 @@ import (
 	"github.com/pion/webrtc/v4/pkg/media"
 )
 
-type sampleWriter interface {
+// SampleWriter receives the frames of a codec, e.g. a
+// webrtc.TrackLocalStaticSample.
+type SampleWriter interface {
 	WriteSample(media.Sample) error
 }
 
-// perfectCodec implements a simple codec that produces frames at a constant rate
+// Perfect implements a simple codec that produces frames at a constant rate
 // with sizes exactly matching the target bitrate.
-type perfectCodec struct {
+type Perfect struct {
 	logger logging.LeveledLogger
 
-	writer sampleWriter
+	writer SampleWriter
 
 	targetBitrateBps int
 	fps              int
@@ -33,9 +36,9 @@ type perfectCodec struct {
 	wg   sync.WaitGroup
 }
 
-// newPerfectCodec creates a new PerfectCodec with the specified frame writer and target bitrate.
-func newPerfectCodec(writer sampleWriter, targetBitrateBps int) *perfectCodec {
-	return &perfectCodec{
+// NewPerfect creates a new Perfect codec with the specified frame writer and target bitrate.
+func NewPerfect(writer SampleWriter, targetBitrateBps int) *Perfect {
+	return &Perfect{
 		logger:           logging.NewDefaultLoggerFactory().NewLogger("perfect_codec"),
 		writer:           writer,
 		targetBitrateBps: targetBitrateBps,
@@ -45,18 +48,20 @@ func newPerfectCodec(writer sampleWriter, targetBitrateBps int) *perfectCodec {
 	}
 }
 
-// setTargetBitrate sets the target bitrate to r bits per second.
-// func (c *perfectCodec) setTargetBitrate(r int) {
-// 	c.wg.Go(func() {
-// 		select {
-// 		case c.bitrateUpdateCh <- r:
-// 		case <-c.done:
-// 		}
-// 	})
-// }
-
-// start begins the codec operation, generating frames at the configured frame rate.
-func (c *perfectCodec) start() {
+// SetTargetBitrate sets the target bitrate to r bits per second.
+func (c *Perfect) SetTargetBitrate(r int) {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		select {
+		case c.bitrateUpdateCh <- r:
+		case <-c.done:
+		}
+	}()
+}
+
+// Start begins the codec operation, generating frames at the configured frame rate.
+func (c *Perfect) Start() {
 	c.wg.Add(1)
 	go func() {
 		defer c.wg.Done()
@@ -90,7 +95,7 @@ func (c *perfectCodec) start() {
 }
 
 // Close stops the codec and cleans up resources.
-func (c *perfectCodec) Close() error {
+func (c *Perfect) Close() error {
 	close(c.done)
 	c.wg.Wait()
 
@@ -3,7 +3,7 @@
 
 //go:build !js
 
-package simulation
+package peer
 
 import (
 	"fmt"
@@ -11,10 +11,32 @@ import (
 	"time"
 
 	"github.com/pion/interceptor"
+	"github.com/pion/interceptor/pkg/packetdump"
 	"github.com/pion/rtcp"
 	"github.com/pion/rtp"
 )
 
+// RegisterPacketLogger logs every RTP and RTCP packet sent and received by the
+// peer with slog.
+func RegisterPacketLogger(vantagePoint string) Option {
+	return func(p *Peer) error {
+		ipl := &packetLogger{vantagePoint: vantagePoint, direction: "in"}
+		rd, err := packetdump.NewReceiverInterceptor(packetdump.PacketLog(ipl))
+		if err != nil {
+			return err
+		}
+		opl := &packetLogger{vantagePoint: vantagePoint, direction: "out"}
+		sd, err := packetdump.NewSenderInterceptor(packetdump.PacketLog(opl))
+		if err != nil {
+			return err
+		}
+		p.interceptorRegistry.Add(rd)
+		p.interceptorRegistry.Add(sd)
+
+		return nil
+	}
+}
+
 type packetLogger struct {
 	vantagePoint string
 	direction    string
@@ -3,22 +3,24 @@
 
 //go:build !js
 
-package simulation
+// Package peer sets up the WebRTC peer connections of the simulation on a
+// virtual network.
+package peer
 
 import (
+	"github.com/pion/bwe/gcc"
 	"github.com/pion/interceptor"
-	"github.com/pion/interceptor/pkg/packetdump"
-	"github.com/pion/interceptor/pkg/rfc8888"
-	"github.com/pion/interceptor/pkg/rtpfb"
 	"github.com/pion/logging"
 	"github.com/pion/transport/v3/vnet"
 	"github.com/pion/webrtc/v4"
 )
 
-type option func(*peer) error
+// Option configures a Peer.
+type Option func(*Peer) error
 
-func setVNet(vnet *vnet.Net, publicIPs []string) option {
-	return func(p *peer) error {
+// SetVNet connects the peer to vnet, where it is reachable at publicIPs.
+func SetVNet(vnet *vnet.Net, publicIPs []string) Option {
+	return func(p *Peer) error {
 		p.settingEngine.SetNet(vnet)
 		p.settingEngine.SetNAT1To1IPs(publicIPs, webrtc.ICECandidateTypeHost)
 
@@ -26,86 +28,85 @@ func setVNet(vnet *vnet.Net, publicIPs []string) option {
 	}
 }
 
-func onRemoteTrack(handler func(*webrtc.TrackRemote)) option {
-	return func(p *peer) error {
+// OnRemoteTrack sets the handler called for every track of the remote peer.
+func OnRemoteTrack(handler func(*webrtc.TrackRemote)) Option {
+	return func(p *Peer) error {
 		p.onRemoteTrack = handler
 
 		return nil
 	}
 }
 
-func onConnected(handler func()) option {
-	return func(p *peer) error {
+// OnConnected sets the handler called when the peer connection is connected.
+func OnConnected(handler func()) Option {
+	return func(p *Peer) error {
 		p.onConnected = handler
 
 		return nil
 	}
 }
 
-func registerDefaultCodecs() option {
-	return func(p *peer) error {
+// RegisterDefaultCodecs registers the default codecs of webrtc.
+func RegisterDefaultCodecs() Option {
+	return func(p *Peer) error {
 		return p.mediaEngine.RegisterDefaultCodecs()
 	}
 }
 
-func registerPacketLogger(vantagePoint string) option {
-	return func(p *peer) error {
-		ipl := &packetLogger{vantagePoint: vantagePoint, direction: "in"}
-		rd, err := packetdump.NewReceiverInterceptor(packetdump.PacketLog(ipl))
-		if err != nil {
-			return err
-		}
-		opl := &packetLogger{vantagePoint: vantagePoint, direction: "out"}
-		sd, err := packetdump.NewSenderInterceptor(packetdump.PacketLog(opl))
+// RegisterInterceptor registers the interceptors created by factory.
+func RegisterInterceptor(factory interceptor.Factory) Option {
+	return func(p *Peer) error {
+		p.interceptorRegistry.Add(factory)
+
+		return nil
+	}
+}
+
+// RegisterGCC registers the GCC interceptor configured by opts.
+func RegisterGCC(opts ...gcc.InterceptorOption) Option {
+	return func(p *Peer) error {
+		gcc, err := gcc.NewInterceptor(opts...)
 		if err != nil {
 			return err
 		}
-		p.interceptorRegistry.Add(rd)
-		p.interceptorRegistry.Add(sd)
+		p.interceptorRegistry.Add(gcc)
 
 		return nil
 	}
 }
 
-func registerRTPFB() option {
-	return func(p *peer) error {
-		rtpfb, err := rtpfb.NewInterceptor()
+const transportCCURI = "http://www.ietf.org/id/draft-holmer-rmcat-transport-wide-cc-extensions-01"
+
+// RegisterTWCCHeaderExtension registers the TWCC header extension for video.
+// It must be registered on both peers to be negotiated. The GCC interceptor
+// sets the transport wide sequence numbers itself, so the sender needs no
+// further interceptor.
+func RegisterTWCCHeaderExtension() Option {
+	return func(p *Peer) error {
+		return p.mediaEngine.RegisterHeaderExtension(
+			webrtc.RTPHeaderExtensionCapability{URI: transportCCURI},
+			webrtc.RTPCodecTypeVideo,
+		)
+	}
+}
+
+// RegisterTWCC registers the interceptor sending TWCC feedback.
+func RegisterTWCC() Option {
+	return func(p *Peer) error {
+		twcc, err := gcc.NewFeedbackInterceptor(gcc.FeedbackReportFormat(gcc.FeedbackTWCC))
 		if err != nil {
 			return err
 		}
-		p.interceptorRegistry.Add(rtpfb)
+		p.interceptorRegistry.Add(twcc)
 
 		return nil
 	}
 }
 
-// func registerTWCC() option {
-// 	return func(p *peer) error {
-// 		twcc, err := twcc.NewSenderInterceptor()
-// 		if err != nil {
-// 			return err
-// 		}
-// 		p.interceptorRegistry.Add(twcc)
-//
-// 		return nil
-// 	}
-// }
-//
-// func registerTWCCHeaderExtension() option {
-// 	return func(p *peer) error {
-// 		twccHdrExt, err := twcc.NewHeaderExtensionInterceptor()
-// 		if err != nil {
-// 			return err
-// 		}
-// 		p.interceptorRegistry.Add(twccHdrExt)
-//
-// 		return nil
-// 	}
-// }
-
-func registerCCFB() option {
-	return func(p *peer) error {
-		ccfb, err := rfc8888.NewSenderInterceptor()
+// RegisterCCFB registers the interceptor sending congestion control feedback.
+func RegisterCCFB() Option {
+	return func(p *Peer) error {
+		ccfb, err := gcc.NewFeedbackInterceptor()
 		if err != nil {
 			return err
 		}
@@ -115,7 +116,8 @@ func registerCCFB() option {
 	}
 }
 
-type peer struct {
+// Peer is a WebRTC peer connection with its configuration.
+type Peer struct {
 	logger logging.LeveledLogger
 	pc     *webrtc.PeerConnection
 
@@ -127,8 +129,9 @@ type peer struct {
 	onConnected   func()
 }
 
-func newPeer(opts ...option) (*peer, error) {
-	peer := &peer{
+// New creates a Peer configured by opts.
+func New(opts ...Option) (*Peer, error) {
+	peer := &Peer{
 		logger:              logging.NewDefaultLoggerFactory().NewLogger("bwe_test_peer"),
 		pc:                  nil,
 		settingEngine:       &webrtc.SettingEngine{},
@@ -173,38 +176,38 @@ func newPeer(opts ...option) (*peer, error) {
 
 // Callbacks
 
-func (p *peer) onNegotiationNeeded() {
+func (p *Peer) onNegotiationNeeded() {
 	p.logger.Infof("negotiation needed")
 }
 
-func (p *peer) onSignalingStateChange(s webrtc.SignalingState) {
+func (p *Peer) onSignalingStateChange(s webrtc.SignalingState) {
 	p.logger.Infof("new signaling state: %v", s)
 }
 
-func (p *peer) onICECandidate(c *webrtc.ICECandidate) {
+func (p *Peer) onICECandidate(c *webrtc.ICECandidate) {
 	p.logger.Infof("got new ICE candidate: %v", c)
 }
 
-func (p *peer) onICEGatheringStateChange(s webrtc.ICEGatheringState) {
+func (p *Peer) onICEGatheringStateChange(s webrtc.ICEGatheringState) {
 	p.logger.Infof("new ICE gathering state: %v", s)
 }
 
-func (p *peer) onICEConnectionStateChange(s webrtc.ICEConnectionState) {
+func (p *Peer) onICEConnectionStateChange(s webrtc.ICEConnectionState) {
 	p.logger.Infof("new ICE connection state: %v", s)
 }
 
-func (p *peer) onConnectionStateChange(s webrtc.PeerConnectionState) {
+func (p *Peer) onConnectionStateChange(s webrtc.PeerConnectionState) {
 	p.logger.Infof("new connection state: %v", s)
 	if s == webrtc.PeerConnectionStateConnected && p.onConnected != nil {
 		p.onConnected()
 	}
 }
 
-func (p *peer) onDataChannel(dc *webrtc.DataChannel) {
+func (p *Peer) onDataChannel(dc *webrtc.DataChannel) {
 	p.logger.Infof("got new data channel: id=%v, label=%v", dc.ID(), dc.Label())
 }
 
-func (p *peer) onTrack(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
+func (p *Peer) onTrack(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
 	if p.onRemoteTrack != nil {
 		p.onRemoteTrack(track)
 	}
@@ -212,7 +215,9 @@ func (p *peer) onTrack(track *webrtc.TrackRemote, _ *webrtc.RTPReceiver) {
 
 // Signaling helpers
 
-func (p *peer) createOffer() (*webrtc.SessionDescription, error) {
+// CreateOffer creates an offer, sets it as local description and returns it
+// once all ICE candidates were gathered.
+func (p *Peer) CreateOffer() (*webrtc.SessionDescription, error) {
 	offer, err := p.pc.CreateOffer(nil)
 	if err != nil {
 		return nil, err
@@ -226,7 +231,9 @@ func (p *peer) createOffer() (*webrtc.SessionDescription, error) {
 	return p.pc.LocalDescription(), nil
 }
 
-func (p *peer) createAnswer() (*webrtc.SessionDescription, error) {
+// CreateAnswer creates an answer, sets it as local description and returns
+// it once all ICE candidates were gathered.
+func (p *Peer) CreateAnswer() (*webrtc.SessionDescription, error) {
 	answer, err := p.pc.CreateAnswer(nil)
 	if err != nil {
 		return nil, err
@@ -240,13 +247,15 @@ func (p *peer) createAnswer() (*webrtc.SessionDescription, error) {
 	return p.pc.LocalDescription(), nil
 }
 
-func (p *peer) setRemoteDescription(description *webrtc.SessionDescription) error {
+// SetRemoteDescription sets the description of the remote peer.
+func (p *Peer) SetRemoteDescription(description *webrtc.SessionDescription) error {
 	return p.pc.SetRemoteDescription(*description)
 }
 
 // Track management
 
-func (p *peer) addLocalTrack() (*webrtc.TrackLocalStaticSample, error) {
+// AddLocalTrack adds an H264 video track to send.
+func (p *Peer) AddLocalTrack() (*webrtc.TrackLocalStaticSample, error) {
 	track, err := webrtc.NewTrackLocalStaticSample(webrtc.RTPCodecCapability{
 		MimeType:     webrtc.MimeTypeH264,
 		ClockRate:    0,
@@ -266,13 +275,19 @@ func (p *peer) addLocalTrack() (*webrtc.TrackLocalStaticSample, error) {
 	return track, err
 }
 
-func (p *peer) addRemoteTrack() error {
+// AddRemoteTrack adds a transceiver to receive a video track.
+func (p *Peer) AddRemoteTrack() error {
 	_, err := p.pc.AddTransceiverFromKind(webrtc.RTPCodecTypeVideo)
 
 	return err
 }
 
-func (p *peer) readRTCP(r *webrtc.RTPSender) {
+// Close closes the peer connection.
+func (p *Peer) Close() error {
+	return p.pc.Close()
+}
+
+func (p *Peer) readRTCP(r *webrtc.RTPSender) {
 	for {
 		_, _, err := r.ReadRTCP()
 		if err != nil {
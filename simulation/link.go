@@ -0,0 +1,600 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pion/transport/v3/vnet"
+)
+
+// udpIPv4HeaderSize is the size of the IPv4 and UDP headers, which are added
+// to the payload of a chunk to get the number of bytes sent on the link.
+const udpIPv4HeaderSize = 28
+
+// traceCapacityWindow is the window over which the capacity of a link
+// replaying a trace is averaged.
+const traceCapacityWindow = time.Second
+
+// redPacketSize is the size in bytes of the typical packet by whose
+// transmission time RED decays the average queue size while the queue is
+// empty.
+const redPacketSize = 500
+
+var errInvalidLinkConfig = errors.New("invalid link config")
+
+// QueueDiscipline selects which packets are dropped when the queue of a link
+// fills up.
+type QueueDiscipline int
+
+const (
+	// DropTail drops packets arriving while the queue is full.
+	DropTail QueueDiscipline = iota
+	// RED drops packets arriving while the queue is filling up with a
+	// probability growing with the average queue size, as described in RFC
+	// 2309.
+	RED
+)
+
+func (q QueueDiscipline) String() string {
+	switch q {
+	case DropTail:
+		return "drop-tail"
+	case RED:
+		return "red"
+	default:
+		return fmt.Sprintf("invalid queue discipline: %d", q)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so queue disciplines are
+// written as "drop-tail" or "red" in scenario files.
+func (q *QueueDiscipline) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "drop-tail":
+		*q = DropTail
+	case "red":
+		*q = RED
+	default:
+		return fmt.Errorf("%w: unknown queue discipline %q", errInvalidLinkConfig, text)
+	}
+
+	return nil
+}
+
+// REDConfig configures random early detection. Thresholds are in bytes of the
+// average queue size.
+type REDConfig struct {
+	// MinThreshold is the average queue size below which no packet is
+	// dropped.
+	MinThreshold int `yaml:"minThreshold"`
+	// MaxThreshold is the average queue size above which every packet is
+	// dropped.
+	MaxThreshold int `yaml:"maxThreshold"`
+	// MaxProbability is the drop probability just below MaxThreshold.
+	MaxProbability float64 `yaml:"maxProbability"`
+	// Weight is the weight of the current queue size in the average. While
+	// the queue is idle, the average decays as if packets of 500 bytes
+	// arrived at the rate of the link.
+	Weight float64 `yaml:"weight"`
+}
+
+// LinkConfig configures a link in one direction.
+type LinkConfig struct {
+	// Bandwidth is the capacity of the link in bits per second. Packets
+	// exceeding the capacity are queued. 0 means unlimited.
+	Bandwidth int
+	// BurstSize is the size of the token bucket in bytes, i.e. the number of
+	// bytes which pass at once after the link was idle. The default is 1500.
+	BurstSize int
+	// QueueSize is the size of the queue in bytes. 0 means unlimited.
+	QueueSize int
+	// Queue selects which packets are dropped when the queue fills up.
+	Queue QueueDiscipline
+	// RED configures random early detection if Queue is RED.
+	RED REDConfig
+
+	// Delay is the fixed one way delay added to every packet after the queue.
+	Delay time.Duration
+	// RandomDelay is the mean of an exponentially distributed delay added to
+	// every packet.
+	RandomDelay time.Duration
+	// Jitter is the largest uniformly distributed delay added to every
+	// packet. Packets are never reordered, so a packet may wait for a previous
+	// packet with a larger delay.
+	Jitter time.Duration
+
+	// Loss drops packets before they enter the queue. nil means no random
+	// loss.
+	Loss LossModel
+
+	// Schedule changes the bandwidth, delay and loss of the link over time.
+	// The bandwidth and delay above apply until its first entry.
+	Schedule Schedule
+
+	// Trace replays the capacity of a recorded link, e.g. one loaded by
+	// LoadMahimahiTrace, instead of limiting it to Bandwidth. Bandwidth and
+	// the bandwidths in the schedule must be 0 then.
+	Trace *Trace
+
+	// Seed seeds the random numbers, so runs with the same seed and traffic
+	// drop and delay the same packets.
+	Seed uint64
+}
+
+func (c LinkConfig) validate() error {
+	if c.Bandwidth < 0 || c.BurstSize < 0 || c.QueueSize < 0 {
+		return fmt.Errorf(
+			"%w: bandwidth %d, burst size %d and queue size %d must not be negative",
+			errInvalidLinkConfig, c.Bandwidth, c.BurstSize, c.QueueSize,
+		)
+	}
+	if c.Delay < 0 || c.RandomDelay < 0 || c.Jitter < 0 {
+		return fmt.Errorf(
+			"%w: delay %v, random delay %v and jitter %v must not be negative",
+			errInvalidLinkConfig, c.Delay, c.RandomDelay, c.Jitter,
+		)
+	}
+	switch c.Queue {
+	case DropTail:
+	case RED:
+		if c.RED.MinThreshold < 0 || c.RED.MinThreshold >= c.RED.MaxThreshold {
+			return fmt.Errorf(
+				"%w: RED thresholds must satisfy 0 <= min < max, got min %d and max %d",
+				errInvalidLinkConfig, c.RED.MinThreshold, c.RED.MaxThreshold,
+			)
+		}
+		if c.RED.MaxProbability <= 0 || c.RED.MaxProbability > 1 {
+			return fmt.Errorf(
+				"%w: RED max probability %v must be in (0, 1]",
+				errInvalidLinkConfig, c.RED.MaxProbability,
+			)
+		}
+		if c.RED.Weight <= 0 || c.RED.Weight > 1 {
+			return fmt.Errorf("%w: RED weight %v must be in (0, 1]", errInvalidLinkConfig, c.RED.Weight)
+		}
+	default:
+		return fmt.Errorf("%w: unknown queue discipline %v", errInvalidLinkConfig, c.Queue)
+	}
+
+	if err := c.Schedule.validate(); err != nil {
+		return err
+	}
+	if c.Trace == nil {
+		return nil
+	}
+	if c.Bandwidth != 0 {
+		return fmt.Errorf("%w: bandwidth %d must be 0 with a trace", errInvalidLinkConfig, c.Bandwidth)
+	}
+	for i, entry := range c.Schedule {
+		if entry.State.Bandwidth != 0 {
+			return fmt.Errorf(
+				"%w: schedule entry %d has bandwidth %d, which must be 0 with a trace",
+				errInvalidLinkConfig, i, entry.State.Bandwidth,
+			)
+		}
+	}
+
+	if err := c.Trace.validate(); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidLinkConfig, err)
+	}
+
+	return nil
+}
+
+// initialState returns the state of the link before the first entry of its
+// schedule.
+func (c LinkConfig) initialState() LinkState {
+	return LinkState{Bandwidth: c.Bandwidth, Delay: c.Delay, Loss: 0}
+}
+
+// LossModel decides which packets a link drops regardless of its queue.
+type LossModel interface {
+	// Drop returns whether the next packet is dropped, drawing random
+	// numbers from rng.
+	Drop(rng *rand.Rand) bool
+}
+
+type bernoulliLoss struct {
+	probability float64
+}
+
+// NewBernoulliLoss returns a LossModel dropping every packet independently
+// with probability.
+func NewBernoulliLoss(probability float64) LossModel {
+	return &bernoulliLoss{probability: probability}
+}
+
+func (l *bernoulliLoss) Drop(rng *rand.Rand) bool {
+	return rng.Float64() < l.probability
+}
+
+type gilbertElliottLoss struct {
+	goodToBad float64
+	badToGood float64
+	goodLoss  float64
+	badLoss   float64
+	bad       bool
+}
+
+// NewGilbertElliottLoss returns a LossModel which drops packets in bursts. It
+// switches from the good to the bad state with probability goodToBad and back
+// with probability badToGood before every packet, and drops the packet with
+// probability goodLoss in the good and badLoss in the bad state.
+func NewGilbertElliottLoss(goodToBad, badToGood, goodLoss, badLoss float64) LossModel {
+	return &gilbertElliottLoss{
+		goodToBad: goodToBad,
+		badToGood: badToGood,
+		goodLoss:  goodLoss,
+		badLoss:   badLoss,
+		bad:       false,
+	}
+}
+
+func (l *gilbertElliottLoss) Drop(rng *rand.Rand) bool {
+	if l.bad {
+		l.bad = rng.Float64() >= l.badToGood
+	} else {
+		l.bad = rng.Float64() < l.goodToBad
+	}
+	if l.bad {
+		return rng.Float64() < l.badLoss
+	}
+
+	return rng.Float64() < l.goodLoss
+}
+
+// queuedPacket is a packet waiting in the queue of a link until departure.
+type queuedPacket struct {
+	departure time.Time
+	size      int
+}
+
+// linkModel computes when packets sent on a link are delivered. It is
+// independent of vnet, so it can be driven by any clock.
+type linkModel struct {
+	config LinkConfig
+	rng    *rand.Rand
+	start  time.Time
+
+	// state is the state of the link according to the schedule, and
+	// capacity the bandwidths it had.
+	state    LinkState
+	capacity []CapacitySample
+
+	// traceIndex is the index of the next delivery opportunity of the trace,
+	// of which traceUsed bytes are used already.
+	traceIndex int
+	traceUsed  int
+
+	tokens        float64
+	tokensUpdated time.Time
+	queue         []queuedPacket
+	queueBytes    int
+	averageQueue  float64
+	// lastDeparture is when the last packet left the queue, so the queue
+	// is idle since then while it is empty.
+	lastDeparture time.Time
+	lastDelivery  time.Time
+}
+
+// newLinkModel returns a new linkModel for a link created at start.
+func newLinkModel(config LinkConfig, start time.Time) (*linkModel, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	if config.BurstSize == 0 {
+		config.BurstSize = 1500
+	}
+
+	state := config.Schedule.stateAt(config.initialState(), 0)
+	var capacity []CapacitySample
+	if config.Trace == nil {
+		capacity = []CapacitySample{{Time: start, Bandwidth: state.Bandwidth}}
+	}
+
+	return &linkModel{
+		config:        config,
+		rng:           rand.New(rand.NewPCG(config.Seed, 0)), // nolint:gosec
+		start:         start,
+		state:         state,
+		capacity:      capacity,
+		traceIndex:    0,
+		traceUsed:     0,
+		tokens:        float64(config.BurstSize),
+		tokensUpdated: time.Time{},
+		queue:         nil,
+		queueBytes:    0,
+		averageQueue:  0,
+		lastDeparture: time.Time{},
+		lastDelivery:  time.Time{},
+	}, nil
+}
+
+// send returns when a packet of size bytes entering the link at now is
+// delivered, or false if the link drops it.
+func (m *linkModel) send(now time.Time, size int) (time.Time, bool) {
+	m.updateState(now)
+	m.dequeue(now)
+	if m.config.Loss != nil && m.config.Loss.Drop(m.rng) {
+		return time.Time{}, false
+	}
+	if m.state.Loss > 0 && m.rng.Float64() < m.state.Loss {
+		return time.Time{}, false
+	}
+	if !m.enqueue(now, size) {
+		return time.Time{}, false
+	}
+	departure := m.departure(now, size)
+	if departure.After(m.lastDeparture) {
+		m.lastDeparture = departure
+	}
+	if departure.After(now) {
+		m.queue = append(m.queue, queuedPacket{departure: departure, size: size})
+		m.queueBytes += size
+	}
+
+	delay := m.state.Delay
+	if m.config.RandomDelay > 0 {
+		delay += time.Duration(m.rng.ExpFloat64() * float64(m.config.RandomDelay))
+	}
+	if m.config.Jitter > 0 {
+		delay += time.Duration(m.rng.Int64N(int64(m.config.Jitter)))
+	}
+	delivery := departure.Add(delay)
+	if delivery.Before(m.lastDelivery) {
+		delivery = m.lastDelivery
+	}
+	m.lastDelivery = delivery
+
+	return delivery, true
+}
+
+// updateState applies the schedule at now and records changes of the
+// capacity.
+func (m *linkModel) updateState(now time.Time) {
+	if len(m.config.Schedule) == 0 {
+		return
+	}
+	m.state = m.config.Schedule.stateAt(m.config.initialState(), now.Sub(m.start))
+	if m.config.Trace == nil && m.state.Bandwidth != m.capacity[len(m.capacity)-1].Bandwidth {
+		m.capacity = append(m.capacity, CapacitySample{Time: now, Bandwidth: m.state.Bandwidth})
+	}
+}
+
+// dequeue removes the packets which left the queue before now.
+func (m *linkModel) dequeue(now time.Time) {
+	i := 0
+	for ; i < len(m.queue) && !m.queue[i].departure.After(now); i++ {
+		m.queueBytes -= m.queue[i].size
+	}
+	m.queue = m.queue[i:]
+}
+
+// enqueue returns whether a packet of size bytes fits into the queue.
+func (m *linkModel) enqueue(now time.Time, size int) bool {
+	if m.state.Bandwidth == 0 && m.config.Trace == nil {
+		return true
+	}
+	if m.config.QueueSize > 0 && m.queueBytes+size > m.config.QueueSize {
+		return false
+	}
+	if m.config.Queue != RED {
+		return true
+	}
+	red := m.config.RED
+	if m.queueBytes == 0 {
+		// RFC 2309 decays the average as if packets of a typical size had
+		// arrived at an empty queue while it was idle.
+		m.averageQueue *= math.Pow(1-red.Weight, m.idlePackets(now))
+	} else {
+		m.averageQueue = (1-red.Weight)*m.averageQueue + red.Weight*float64(m.queueBytes)
+	}
+	switch {
+	case m.averageQueue < float64(red.MinThreshold):
+		return true
+	case m.averageQueue >= float64(red.MaxThreshold):
+		return false
+	default:
+		probability := red.MaxProbability * (m.averageQueue - float64(red.MinThreshold)) /
+			float64(red.MaxThreshold-red.MinThreshold)
+
+		return m.rng.Float64() >= probability
+	}
+}
+
+// idlePackets returns how many packets of redPacketSize the link could have
+// sent between the departure of the last packet and now.
+func (m *linkModel) idlePackets(now time.Time) float64 {
+	if m.lastDeparture.IsZero() || !now.After(m.lastDeparture) {
+		return 0
+	}
+	bandwidth := m.state.Bandwidth
+	if m.config.Trace != nil {
+		bandwidth = m.config.Trace.bandwidth(m.lastDeparture.Sub(m.start), now.Sub(m.start))
+	}
+
+	return float64(bandwidth) / 8 * now.Sub(m.lastDeparture).Seconds() / redPacketSize
+}
+
+// departure returns when a packet of size bytes entering the link at now
+// leaves the token bucket.
+func (m *linkModel) departure(now time.Time, size int) time.Time {
+	if m.config.Trace != nil {
+		return m.traceDeparture(now, size)
+	}
+	if m.state.Bandwidth == 0 {
+		return now
+	}
+	bytesPerSecond := float64(m.state.Bandwidth) / 8
+	// The packet departs after the packets queued before it.
+	departure := now
+	if m.tokensUpdated.After(departure) {
+		departure = m.tokensUpdated
+	}
+	if !m.tokensUpdated.IsZero() {
+		m.tokens += departure.Sub(m.tokensUpdated).Seconds() * bytesPerSecond
+	}
+	m.tokens = min(m.tokens, float64(max(m.config.BurstSize, size)))
+	if m.tokens < float64(size) {
+		wait := (float64(size) - m.tokens) / bytesPerSecond
+		departure = departure.Add(time.Duration(math.Ceil(wait * float64(time.Second))))
+		m.tokens = float64(size)
+	}
+	m.tokens -= float64(size)
+	m.tokensUpdated = departure
+
+	return departure
+}
+
+// traceDeparture returns when a packet of size bytes entering the link at now
+// is sent in the delivery opportunities of the trace.
+func (m *linkModel) traceDeparture(now time.Time, size int) time.Time {
+	trace := m.config.Trace
+	// Opportunities passing while the queue was empty are wasted.
+	if skipped := int(now.Sub(m.start)/trace.Period) * len(trace.Opportunities); skipped > m.traceIndex {
+		m.traceIndex = skipped
+		m.traceUsed = 0
+	}
+	for {
+		at, _ := trace.opportunityAt(m.traceIndex)
+		if !m.start.Add(at).Before(now) {
+			break
+		}
+		m.traceIndex++
+		m.traceUsed = 0
+	}
+	// A packet larger than the free bytes of an opportunity continues in the
+	// next ones and departs with its last byte.
+	for {
+		at, opportunitySize := trace.opportunityAt(m.traceIndex)
+		if free := opportunitySize - m.traceUsed; size > free {
+			size -= free
+			m.traceIndex++
+			m.traceUsed = 0
+
+			continue
+		}
+		m.traceUsed += size
+
+		return m.start.Add(at)
+	}
+}
+
+// Link emulates a link towards the vnet.Nets added with AddNet. Packets routed
+// to such a Net are dropped by the loss model and the queue, and delayed by
+// the queue and the configured delays, like on a real bottleneck link. The
+// emulation only depends on the virtual time and the seed, so it is
+// deterministic under testing/synctest.
+type Link struct {
+	lock    sync.Mutex
+	model   *linkModel
+	filters []*vnet.DelayFilter
+}
+
+// NewLink returns a new Link configured by config.
+func NewLink(config LinkConfig) (*Link, error) {
+	model, err := newLinkModel(config, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	return &Link{
+		lock:    sync.Mutex{},
+		model:   model,
+		filters: nil,
+	}, nil
+}
+
+// AddNet creates a vnet.Net with the static IP ip, adds it to router and routes
+// all packets the router forwards to it through the link.
+func (l *Link) AddNet(router *vnet.Router, ip string) (*vnet.Net, error) {
+	nic, err := vnet.NewNet(&vnet.NetConfig{
+		StaticIPs: []string{ip},
+		StaticIP:  "",
+	})
+	if err != nil {
+		return nil, err
+	}
+	// vnet does not allow custom NICs, so the packets are delayed by a
+	// DelayFilter whose delay is set to the delay of each packet right before
+	// the router hands it over. The router forwards packets one at a time,
+	// so the delay of a packet is never overwritten before it is queued.
+	filter, err := vnet.NewDelayFilter(nic, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = router.AddNet(filter); err != nil {
+		return nil, errors.Join(err, filter.Close())
+	}
+	destination := net.ParseIP(ip)
+	router.AddChunkFilter(func(c vnet.Chunk) bool {
+		addr, ok := c.DestinationAddr().(*net.UDPAddr)
+		if !ok || !addr.IP.Equal(destination) {
+			return true
+		}
+		now := time.Now()
+		l.lock.Lock()
+		delivery, ok := l.model.send(now, len(c.UserData())+udpIPv4HeaderSize)
+		l.lock.Unlock()
+		if ok {
+			filter.SetDelay(delivery.Sub(now))
+		}
+
+		return ok
+	})
+	l.lock.Lock()
+	l.filters = append(l.filters, filter)
+	l.lock.Unlock()
+
+	return nic, nil
+}
+
+// Capacity returns the bandwidth of the link at at according to its schedule,
+// which is the ground truth to compare estimates to. For a link replaying a
+// trace, it is the average bandwidth of the trace in the second from at.
+func (l *Link) Capacity(at time.Time) int {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	config := l.model.config
+	if config.Trace != nil {
+		elapsed := at.Sub(l.model.start)
+
+		return config.Trace.bandwidth(elapsed, elapsed+traceCapacityWindow)
+	}
+
+	return config.Schedule.stateAt(config.initialState(), at.Sub(l.model.start)).Bandwidth
+}
+
+// CapacityLog returns the bandwidths the link had while packets were sent,
+// starting with the bandwidth at its creation. It is empty for a link
+// replaying a trace.
+func (l *Link) CapacityLog() []CapacitySample {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return append([]CapacitySample(nil), l.model.capacity...)
+}
+
+// Close stops delaying packets. Packets still delayed are delivered
+// immediately.
+func (l *Link) Close() error {
+	l.lock.Lock()
+	filters := l.filters
+	l.filters = nil
+	l.lock.Unlock()
+
+	var err error
+	for _, filter := range filters {
+		err = errors.Join(err, filter.Close())
+	}
+
+	return err
+}
@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js && go1.25
+
+package simulation
+
+import (
+	"net"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3/vnet"
+	"github.com/stretchr/testify/assert"
+)
+
+// sendConstantRate sends count packets of size bytes every interval through m
+// and returns the delivery times, where the zero time marks a dropped packet.
+func sendConstantRate(
+	t *testing.T,
+	m *linkModel,
+	start time.Time,
+	count, size int,
+	interval time.Duration,
+) []time.Time {
+	t.Helper()
+
+	deliveries := make([]time.Time, 0, count)
+	for i := 0; i < count; i++ {
+		delivery, ok := m.send(start.Add(time.Duration(i)*interval), size)
+		if !ok {
+			delivery = time.Time{}
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries
+}
+
+func TestLinkModelBandwidth(t *testing.T) {
+	m, err := newLinkModel(LinkConfig{Bandwidth: 1_000_000, Delay: 20 * time.Millisecond}, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	// 1250 bytes every 5ms is twice the bandwidth, so the packets leave the
+	// queue every 10ms once the burst is used up.
+	deliveries := sendConstantRate(t, m, start, 100, 1250, 5*time.Millisecond)
+	assert.Equal(t, start.Add(20*time.Millisecond), deliveries[0])
+	for i := 1; i < len(deliveries); i++ {
+		expected := start.Add(20*time.Millisecond + 8*time.Millisecond + time.Duration(i-1)*10*time.Millisecond)
+		assert.Equal(t, expected, deliveries[i], "packet %v", i)
+	}
+}
+
+func TestLinkModelBurst(t *testing.T) {
+	m, err := newLinkModel(LinkConfig{Bandwidth: 1_000_000, BurstSize: 5000}, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	// The first four packets fit into the bucket.
+	deliveries := sendConstantRate(t, m, start, 5, 1250, 0)
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, start, deliveries[i])
+	}
+	assert.Equal(t, start.Add(10*time.Millisecond), deliveries[4])
+}
+
+func TestLinkModelDropTail(t *testing.T) {
+	m, err := newLinkModel(LinkConfig{Bandwidth: 1_000_000, QueueSize: 5000}, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	deliveries := sendConstantRate(t, m, start, 400, 1250, 5*time.Millisecond)
+	dropped := 0
+	for i, delivery := range deliveries {
+		if delivery.IsZero() {
+			dropped++
+
+			continue
+		}
+		// At most four packets are queued ahead.
+		sent := start.Add(time.Duration(i) * 5 * time.Millisecond)
+		assert.LessOrEqual(t, delivery.Sub(sent), 50*time.Millisecond, "packet %v", i)
+	}
+	// Half of the packets exceed the bandwidth.
+	assert.InDelta(t, 200, dropped, 5)
+}
+
+func TestLinkModelRED(t *testing.T) {
+	config := LinkConfig{
+		Bandwidth: 1_000_000,
+		QueueSize: 20_000,
+		Queue:     RED,
+		RED: REDConfig{
+			MinThreshold:   2500,
+			MaxThreshold:   10_000,
+			MaxProbability: 0.1,
+			Weight:         0.2,
+		},
+		Seed: 1,
+	}
+	m, err := newLinkModel(config, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	deliveries := sendConstantRate(t, m, start, 400, 1250, 5*time.Millisecond)
+	longestDelay := time.Duration(0)
+	dropped := 0
+	for i, delivery := range deliveries {
+		if delivery.IsZero() {
+			dropped++
+
+			continue
+		}
+		longestDelay = max(longestDelay, delivery.Sub(start.Add(time.Duration(i)*5*time.Millisecond)))
+	}
+	assert.InDelta(t, 200, dropped, 15)
+	// The queue is kept well below its size of 160ms.
+	assert.Less(t, longestDelay, 100*time.Millisecond)
+}
+
+func TestLinkModelREDIdle(t *testing.T) {
+	m, err := newLinkModel(LinkConfig{
+		Bandwidth: 1_000_000,
+		QueueSize: 20_000,
+		Queue:     RED,
+		RED: REDConfig{
+			MinThreshold:   2500,
+			MaxThreshold:   10_000,
+			MaxProbability: 0.1,
+			Weight:         0.2,
+		},
+		Seed: 1,
+	}, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	// A burst fills the queue until the average exceeds the max threshold.
+	for m.averageQueue < 10_000 {
+		m.send(start, 1250)
+	}
+	// The queue drains in 160ms. After a second in which the link could have
+	// sent 250 packets of 500 bytes, the average has decayed.
+	_, ok := m.send(start.Add(time.Second), 1250)
+	assert.True(t, ok)
+	assert.InDelta(t, 0, m.averageQueue, 1)
+
+	// A packet arriving at an empty queue right after the last departure
+	// does not decay the average.
+	m.averageQueue = 10_000
+	m.lastDeparture = start.Add(2 * time.Second)
+	_, ok = m.send(start.Add(2*time.Second), 1250)
+	assert.False(t, ok)
+}
+
+func TestLinkModelDelay(t *testing.T) {
+	m, err := newLinkModel(LinkConfig{
+		Delay:  50 * time.Millisecond,
+		Jitter: 20 * time.Millisecond,
+		Seed:   1,
+	}, time.Time{})
+	assert.NoError(t, err)
+	start := time.Time{}.Add(time.Second)
+
+	deliveries := sendConstantRate(t, m, start, 1000, 1200, time.Millisecond)
+	for i, delivery := range deliveries {
+		delay := delivery.Sub(start.Add(time.Duration(i) * time.Millisecond))
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		// A packet waits at most for its predecessors.
+		assert.Less(t, delay, 70*time.Millisecond)
+		if i > 0 {
+			assert.False(t, delivery.Before(deliveries[i-1]), "packet %v reordered", i)
+		}
+	}
+
+	m, err = newLinkModel(LinkConfig{RandomDelay: 10 * time.Millisecond, Seed: 1}, time.Time{})
+	assert.NoError(t, err)
+	// Packets are sent far apart, so they do not wait for each other.
+	deliveries = sendConstantRate(t, m, start, 1000, 1200, time.Second)
+	total := time.Duration(0)
+	for i, delivery := range deliveries {
+		total += delivery.Sub(start.Add(time.Duration(i) * time.Second))
+	}
+	assert.InDelta(t, float64(10*time.Millisecond), float64(total/1000), float64(time.Millisecond))
+}
+
+// lossBursts returns the loss rate and the average number of consecutive
+// losses of model over count packets.
+func lossBursts(t *testing.T, model LossModel, count int) (float64, float64) {
+	t.Helper()
+
+	m, err := newLinkModel(LinkConfig{Loss: model, Seed: 1}, time.Time{})
+	assert.NoError(t, err)
+	lost := 0
+	bursts := 0
+	previousLost := false
+	for i := 0; i < count; i++ {
+		_, ok := m.send(time.Time{}, 1200)
+		if !ok {
+			lost++
+			if !previousLost {
+				bursts++
+			}
+		}
+		previousLost = !ok
+	}
+
+	return float64(lost) / float64(count), float64(lost) / float64(bursts)
+}
+
+func TestLinkModelLoss(t *testing.T) {
+	rate, burstLength := lossBursts(t, NewBernoulliLoss(0.1), 100_000)
+	assert.InDelta(t, 0.1, rate, 0.005)
+	assert.InDelta(t, 1.11, burstLength, 0.05)
+
+	// The bad state lasts 10 packets on average and is entered after 90
+	// packets on average, so 10% of the packets are sent in the bad state.
+	rate, burstLength = lossBursts(t, NewGilbertElliottLoss(1.0/90, 0.1, 0, 1), 100_000)
+	assert.InDelta(t, 0.1, rate, 0.02)
+	assert.InDelta(t, 10, burstLength, 1.5)
+}
+
+func TestLinkModelDeterministic(t *testing.T) {
+	config := func() LinkConfig {
+		return LinkConfig{
+			Bandwidth: 1_000_000,
+			QueueSize: 10_000,
+			Jitter:    10 * time.Millisecond,
+			Loss:      NewGilbertElliottLoss(0.01, 0.2, 0.001, 0.5),
+			Seed:      42,
+		}
+	}
+	start := time.Time{}.Add(time.Second)
+	a, err := newLinkModel(config(), time.Time{})
+	assert.NoError(t, err)
+	b, err := newLinkModel(config(), time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t,
+		sendConstantRate(t, a, start, 1000, 1200, 5*time.Millisecond),
+		sendConstantRate(t, b, start, 1000, 1200, 5*time.Millisecond),
+	)
+}
+
+func TestLinkConfigValidate(t *testing.T) {
+	validRED := REDConfig{MinThreshold: 1000, MaxThreshold: 5000, MaxProbability: 0.1, Weight: 0.002}
+	cases := []struct {
+		name   string
+		config LinkConfig
+		valid  bool
+	}{
+		{name: "zero", config: LinkConfig{}, valid: true},
+		{name: "red", config: LinkConfig{Queue: RED, RED: validRED}, valid: true},
+		{name: "negativeBandwidth", config: LinkConfig{Bandwidth: -1}, valid: false},
+		{name: "negativeQueueSize", config: LinkConfig{QueueSize: -1}, valid: false},
+		{name: "negativeDelay", config: LinkConfig{Delay: -1}, valid: false},
+		{name: "negativeJitter", config: LinkConfig{Jitter: -1}, valid: false},
+		{name: "unknownQueue", config: LinkConfig{Queue: 7}, valid: false},
+		{name: "redWithoutThresholds", config: LinkConfig{Queue: RED}, valid: false},
+		{
+			name:   "redMinAboveMax",
+			config: LinkConfig{Queue: RED, RED: REDConfig{MinThreshold: 5000, MaxThreshold: 1000, MaxProbability: 0.1}},
+			valid:  false,
+		},
+		{
+			name: "redWithoutWeight",
+			config: LinkConfig{Queue: RED, RED: REDConfig{
+				MinThreshold:   1000,
+				MaxThreshold:   5000,
+				MaxProbability: 0.1,
+			}},
+			valid: false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			link, err := NewLink(tc.config)
+			if tc.valid {
+				assert.NoError(t, err)
+				assert.NoError(t, link.Close())
+
+				return
+			}
+			assert.ErrorIs(t, err, errInvalidLinkConfig)
+			assert.Nil(t, link)
+		})
+	}
+}
+
+func TestLink(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		wan, err := vnet.NewRouter(&vnet.RouterConfig{
+			CIDR:          "10.0.0.0/24",
+			LoggerFactory: logging.NewDefaultLoggerFactory(),
+		})
+		assert.NoError(t, err)
+		link, err := NewLink(LinkConfig{Bandwidth: 1_000_000, Delay: 20 * time.Millisecond})
+		assert.NoError(t, err)
+		receiverNet, err := link.AddNet(wan, "10.0.0.1")
+		assert.NoError(t, err)
+		senderNet, err := vnet.NewNet(&vnet.NetConfig{StaticIPs: []string{"10.0.0.2"}})
+		assert.NoError(t, err)
+		assert.NoError(t, wan.AddNet(senderNet))
+		assert.NoError(t, wan.Start())
+
+		receiver, err := receiverNet.ListenPacket("udp4", "10.0.0.1:5000")
+		assert.NoError(t, err)
+		sender, err := senderNet.ListenPacket("udp4", "10.0.0.2:5000")
+		assert.NoError(t, err)
+
+		// 10 packets of 1250 bytes on the link sent at once leave the queue
+		// 10ms apart, once the first used up the burst.
+		start := time.Now()
+		for i := 0; i < 10; i++ {
+			_, err = sender.WriteTo(make([]byte, 1250-udpIPv4HeaderSize), &net.UDPAddr{
+				IP:   net.ParseIP("10.0.0.1"),
+				Port: 5000,
+			})
+			assert.NoError(t, err)
+		}
+		buf := make([]byte, 1500)
+		for i := 0; i < 10; i++ {
+			_, _, err = receiver.ReadFrom(buf)
+			assert.NoError(t, err)
+			expected := 20 * time.Millisecond
+			if i > 0 {
+				expected += 8*time.Millisecond + time.Duration(i-1)*10*time.Millisecond
+			}
+			assert.Equal(t, expected, time.Since(start))
+		}
+
+		assert.NoError(t, sender.Close())
+		assert.NoError(t, receiver.Close())
+		assert.NoError(t, wan.Stop())
+		assert.NoError(t, link.Close())
+		synctest.Wait()
+	})
+}
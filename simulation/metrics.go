@@ -0,0 +1,369 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+const (
+	defaultConvergenceTolerance = 0.2
+	defaultConvergenceHold      = time.Second
+)
+
+var (
+	errInvalidMetricsConfig = errors.New("invalid metrics config")
+	errThresholdViolated    = errors.New("threshold violated")
+)
+
+// MetricsConfig configures how the metrics of a Result are computed.
+type MetricsConfig struct {
+	// From skips the samples of the intervals ending in the first From of
+	// the scenario, e.g. to exclude the ramp up from the utilization and the
+	// fairness.
+	From time.Duration
+	// ConvergenceTolerance is the fraction of the capacity by which the sum
+	// of the target bitrates of the sending flows may deviate from the
+	// capacity to be converged. The default is 0.2.
+	ConvergenceTolerance float64
+	// ConvergenceHold is how long the target bitrates must stay within the
+	// tolerance to be converged. The default is 1s.
+	ConvergenceHold time.Duration
+}
+
+// Metrics are the evaluation criteria of RFC 8868 computed from a Result.
+// Queue delays are one way delays above the smallest one way delay of the
+// flow, so they exclude the propagation delay.
+type Metrics struct {
+	// Utilization is the mean fraction of the capacity of the forward link
+	// used by the receive rates of all flows. Samples of a link without a
+	// bandwidth limit are skipped.
+	Utilization    float64
+	MeanQueueDelay time.Duration
+	P95QueueDelay  time.Duration
+	// LossRate is the mean loss rate of the samples with traffic.
+	LossRate float64
+	// CapacityChanges holds the convergence after every change of the
+	// capacity, including the changes before From.
+	CapacityChanges []CapacityChange
+	// Fairness is Jain's fairness index of the mean receive rates of the
+	// flows. It is 1 if all flows received the same rate.
+	Fairness float64
+	// SelfFairness is the mean of Jain's fairness index of the receive
+	// rates of the flows active in each sample, i.e. how evenly flows of the
+	// same controller share the link at any time, over the samples in which
+	// at least two flows were active. It is 1 if there are no such samples.
+	SelfFairness float64
+	Flows        []FlowMetrics
+}
+
+// FlowMetrics are the metrics of a single flow.
+type FlowMetrics struct {
+	Name            string
+	MeanReceiveRate int
+	MeanQueueDelay  time.Duration
+	P95QueueDelay   time.Duration
+	LossRate        float64
+}
+
+// CapacityChange is a change of the capacity of the forward link. A ramp is a
+// single change which ends when the capacity stops changing.
+type CapacityChange struct {
+	// At is the time of the first sample with a changed capacity, which is
+	// the start of a ramp.
+	At       time.Time
+	From, To int
+	// ConvergenceTime is the time from At until the sum of the target
+	// bitrates of the sending flows entered the tolerance around To and
+	// stayed there for the hold time. The receive rates are no measure
+	// of convergence, as the queue keeps the link busy after a drop. It is
+	// only valid if Converged is true.
+	ConvergenceTime time.Duration
+	Converged       bool
+}
+
+// Thresholds are the limits a scenario must meet. Zero values are not
+// checked.
+type Thresholds struct {
+	MinUtilization     float64
+	MaxMeanQueueDelay  time.Duration
+	MaxP95QueueDelay   time.Duration
+	MaxLossRate        float64
+	MaxConvergenceTime time.Duration
+	MinFairness        float64
+	MinSelfFairness    float64
+}
+
+// Metrics computes the metrics of the result.
+func (r *Result) Metrics(config MetricsConfig) (*Metrics, error) {
+	if config.From < 0 {
+		return nil, fmt.Errorf("%w: from %v must not be negative", errInvalidMetricsConfig, config.From)
+	}
+	if config.ConvergenceTolerance < 0 || config.ConvergenceHold < 0 {
+		return nil, fmt.Errorf(
+			"%w: convergence tolerance %v and hold %v must not be negative",
+			errInvalidMetricsConfig, config.ConvergenceTolerance, config.ConvergenceHold,
+		)
+	}
+	if config.ConvergenceTolerance == 0 {
+		config.ConvergenceTolerance = defaultConvergenceTolerance
+	}
+	if config.ConvergenceHold == 0 {
+		config.ConvergenceHold = defaultConvergenceHold
+	}
+	from := r.Start.Add(config.From)
+
+	metrics := &Metrics{
+		Utilization:     0,
+		MeanQueueDelay:  0,
+		P95QueueDelay:   0,
+		LossRate:        0,
+		CapacityChanges: r.capacityChanges(config),
+		Fairness:        1,
+		SelfFairness:    1,
+		Flows:           make([]FlowMetrics, 0, len(r.Flows)),
+	}
+	allQueueDelays := []time.Duration{}
+	allLosses := []float64{}
+	meanRates := make([]float64, 0, len(r.Flows))
+	for _, flow := range r.Flows {
+		queueDelays, losses, meanRate := flowSamples(flow.Samples, from)
+		metrics.Flows = append(metrics.Flows, FlowMetrics{
+			Name:            flow.Name,
+			MeanReceiveRate: int(meanRate),
+			MeanQueueDelay:  meanDuration(queueDelays),
+			P95QueueDelay:   percentile(queueDelays, 0.95),
+			LossRate:        mean(losses),
+		})
+		allQueueDelays = append(allQueueDelays, queueDelays...)
+		allLosses = append(allLosses, losses...)
+		meanRates = append(meanRates, meanRate)
+	}
+	metrics.MeanQueueDelay = meanDuration(allQueueDelays)
+	metrics.P95QueueDelay = percentile(allQueueDelays, 0.95)
+	metrics.LossRate = mean(allLosses)
+	metrics.Fairness = jainIndex(meanRates)
+	metrics.Utilization, metrics.SelfFairness = r.sharing(from)
+
+	return metrics, nil
+}
+
+// flowSamples returns the queue delays and losses of the samples with traffic
+// after from, and the mean receive rate of all samples after from.
+func flowSamples(samples []Sample, from time.Time) ([]time.Duration, []float64, float64) {
+	baseDelay := time.Duration(math.MaxInt64)
+	for _, sample := range samples {
+		if sample.ReceiveRate > 0 {
+			baseDelay = min(baseDelay, sample.OneWayDelay)
+		}
+	}
+	queueDelays := []time.Duration{}
+	losses := []float64{}
+	rates := []float64{}
+	for _, sample := range samples {
+		if !sample.Time.After(from) {
+			continue
+		}
+		rates = append(rates, float64(sample.ReceiveRate))
+		if sample.ReceiveRate > 0 {
+			queueDelays = append(queueDelays, sample.OneWayDelay-baseDelay)
+		}
+		if sample.ReceiveRate > 0 || sample.Loss > 0 {
+			losses = append(losses, sample.Loss)
+		}
+	}
+
+	return queueDelays, losses, mean(rates)
+}
+
+// sharing returns the utilization of the capacity and the self-fairness of
+// the samples after from.
+func (r *Result) sharing(from time.Time) (float64, float64) {
+	utilizations := []float64{}
+	fairness := []float64{}
+	for i, capacity := range r.Capacity {
+		if !capacity.Time.After(from) {
+			continue
+		}
+		active := []float64{}
+		total := 0
+		for _, flow := range r.Flows {
+			if i >= len(flow.Samples) {
+				continue
+			}
+			if rate := flow.Samples[i].ReceiveRate; rate > 0 {
+				active = append(active, float64(rate))
+				total += rate
+			}
+		}
+		if capacity.Bandwidth > 0 {
+			utilizations = append(utilizations, float64(total)/float64(capacity.Bandwidth))
+		}
+		if len(active) > 1 {
+			fairness = append(fairness, jainIndex(active))
+		}
+	}
+	selfFairness := 1.0
+	if len(fairness) > 0 {
+		selfFairness = mean(fairness)
+	}
+
+	return mean(utilizations), selfFairness
+}
+
+// capacityChanges returns the changes of the capacity and whether the flows
+// converged to them.
+func (r *Result) capacityChanges(config MetricsConfig) []CapacityChange {
+	changes := []CapacityChange{}
+	for i := 1; i < len(r.Capacity); i++ {
+		previous := r.Capacity[i-1].Bandwidth
+		if r.Capacity[i].Bandwidth == previous {
+			continue
+		}
+		// Skip to the end of a ramp.
+		start := i
+		for i+1 < len(r.Capacity) && r.Capacity[i+1].Bandwidth != r.Capacity[i].Bandwidth {
+			i++
+		}
+		change := CapacityChange{
+			At:              r.Capacity[start].Time,
+			From:            previous,
+			To:              r.Capacity[i].Bandwidth,
+			ConvergenceTime: 0,
+			Converged:       false,
+		}
+		change.ConvergenceTime, change.Converged = r.convergence(start, i, config)
+		changes = append(changes, change)
+	}
+
+	return changes
+}
+
+// convergence returns how long after the sample start the sum of the target
+// bitrates entered the tolerance around the capacity reached at the sample
+// end for the hold time, before the capacity changed again.
+func (r *Result) convergence(start, end int, config MetricsConfig) (time.Duration, bool) {
+	capacity := float64(r.Capacity[end].Bandwidth)
+	entered := -1
+	for i := end; i < len(r.Capacity) && r.Capacity[i].Bandwidth == r.Capacity[end].Bandwidth; i++ {
+		total := 0
+		for _, flow := range r.Flows {
+			if i < len(flow.Samples) && flow.Samples[i].SendRate > 0 {
+				total += flow.Samples[i].TargetBitrate
+			}
+		}
+		if math.Abs(float64(total)-capacity) > config.ConvergenceTolerance*capacity {
+			entered = -1
+
+			continue
+		}
+		if entered < 0 {
+			entered = i
+		}
+		if r.Capacity[i].Time.Sub(r.Capacity[entered].Time) >= config.ConvergenceHold {
+			return r.Capacity[entered].Time.Sub(r.Capacity[start].Time), true
+		}
+	}
+
+	return 0, false
+}
+
+// Check returns an error describing every threshold the metrics violate.
+func (m *Metrics) Check(thresholds Thresholds) error {
+	var errs []error
+	violated := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("%w: "+format, append([]any{errThresholdViolated}, args...)...))
+	}
+	if thresholds.MinUtilization > 0 && m.Utilization < thresholds.MinUtilization {
+		violated("utilization %.3f below %.3f", m.Utilization, thresholds.MinUtilization)
+	}
+	if thresholds.MaxMeanQueueDelay > 0 && m.MeanQueueDelay > thresholds.MaxMeanQueueDelay {
+		violated("mean queue delay %v above %v", m.MeanQueueDelay, thresholds.MaxMeanQueueDelay)
+	}
+	if thresholds.MaxP95QueueDelay > 0 && m.P95QueueDelay > thresholds.MaxP95QueueDelay {
+		violated("95th percentile queue delay %v above %v", m.P95QueueDelay, thresholds.MaxP95QueueDelay)
+	}
+	if thresholds.MaxLossRate > 0 && m.LossRate > thresholds.MaxLossRate {
+		violated("loss rate %.4f above %.4f", m.LossRate, thresholds.MaxLossRate)
+	}
+	if thresholds.MaxConvergenceTime > 0 {
+		for _, change := range m.CapacityChanges {
+			switch {
+			case !change.Converged:
+				violated("no convergence after the capacity changed from %d to %d", change.From, change.To)
+			case change.ConvergenceTime > thresholds.MaxConvergenceTime:
+				violated(
+					"convergence time %v after the capacity changed from %d to %d above %v",
+					change.ConvergenceTime, change.From, change.To, thresholds.MaxConvergenceTime,
+				)
+			}
+		}
+	}
+	if thresholds.MinFairness > 0 && m.Fairness < thresholds.MinFairness {
+		violated("fairness %.3f below %.3f", m.Fairness, thresholds.MinFairness)
+	}
+	if thresholds.MinSelfFairness > 0 && m.SelfFairness < thresholds.MinSelfFairness {
+		violated("self-fairness %.3f below %.3f", m.SelfFairness, thresholds.MinSelfFairness)
+	}
+
+	return errors.Join(errs...)
+}
+
+// jainIndex returns Jain's fairness index (sum x)^2 / (n * sum x^2) of rates,
+// or 1 if all rates are 0.
+func jainIndex(rates []float64) float64 {
+	sum, squares := 0.0, 0.0
+	for _, rate := range rates {
+		sum += rate
+		squares += rate * rate
+	}
+	if squares == 0 {
+		return 1
+	}
+
+	return sum * sum / (float64(len(rates)) * squares)
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, value := range values {
+		sum += value
+	}
+
+	return sum / float64(len(values))
+}
+
+func meanDuration(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sum := time.Duration(0)
+	for _, d := range durations {
+		sum += d
+	}
+
+	return sum / time.Duration(len(durations))
+}
+
+// percentile returns the p-th percentile of durations by the nearest rank
+// method.
+func percentile(durations []time.Duration, p float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(durations)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(p * float64(len(sorted))))
+
+	return sorted[max(rank, 1)-1]
+}
@@ -0,0 +1,232 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+
+package simulation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pion/interceptor"
+	"github.com/pion/rtp"
+)
+
+// Sample is the state of a flow in the interval ending at Time.
+type Sample struct {
+	Time time.Time
+	// TargetBitrate is the target bitrate of the controller in bits per
+	// second.
+	TargetBitrate int
+	// SendRate is the rate at which the sender sent RTP packets in bits per
+	// second, including headers, padding and probes.
+	SendRate int
+	// ReceiveRate is the rate at which the receiver received RTP packets in
+	// bits per second.
+	ReceiveRate int
+	// RTT is the latest round trip time measured by the controller.
+	RTT time.Duration
+	// OneWayDelay is the mean time the RTP packets received in the interval
+	// took from the sender to the receiver, or 0 if none was received. Only
+	// packets whose departure is still known count.
+	OneWayDelay time.Duration
+	// Loss is the fraction of the RTP packets expected in the interval which
+	// were not received.
+	Loss float64
+}
+
+// maxDepartures is the number of packets per flow whose departure is kept to
+// measure their delay, several seconds worth of packets at 10 Mbps.
+const maxDepartures = 1 << 12
+
+type departureKey struct {
+	ssrc           uint32
+	sequenceNumber uint16
+}
+
+type departure struct {
+	key  departureKey
+	time time.Time
+}
+
+// flowRecorder records the packets of a flow at both ends to sample its
+// rates, delay and loss. Sender and receiver share the virtual clock, so the
+// one way delay is measured directly.
+type flowRecorder struct {
+	lock sync.Mutex
+
+	targetBitrate int
+	rtt           time.Duration
+
+	// departures holds the departure of the last maxDepartures packets sent,
+	// which order holds in a ring in the order they were sent, so the oldest
+	// one is evicted first.
+	departures map[departureKey]time.Time
+	order      []departure
+	nextOrder  int
+
+	sentBytes     int
+	receivedBytes int
+	received      int
+	// delaySum is the sum of the delays of the matched packets received,
+	// whose departure was known.
+	delaySum time.Duration
+	matched  int
+
+	// highestSequence holds the highest sequence number received per SSRC,
+	// so padding on an RTX stream does not count as loss on the media stream.
+	highestSequence map[uint32]uint16
+	expected        int
+}
+
+func newFlowRecorder(initialBitrate int) *flowRecorder {
+	return &flowRecorder{
+		lock:            sync.Mutex{},
+		targetBitrate:   initialBitrate,
+		rtt:             0,
+		departures:      make(map[departureKey]time.Time, maxDepartures),
+		order:           make([]departure, 0, maxDepartures),
+		nextOrder:       0,
+		sentBytes:       0,
+		receivedBytes:   0,
+		received:        0,
+		delaySum:        0,
+		matched:         0,
+		highestSequence: map[uint32]uint16{},
+		expected:        0,
+	}
+}
+
+func (r *flowRecorder) onTargetBitrate(bitrate int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.targetBitrate = bitrate
+}
+
+func (r *flowRecorder) onRTT(rtt time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.rtt = rtt
+}
+
+func (r *flowRecorder) onSent(now time.Time, header *rtp.Header, size int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	key := departureKey{ssrc: header.SSRC, sequenceNumber: header.SequenceNumber}
+	if len(r.order) < maxDepartures {
+		r.order = append(r.order, departure{key: key, time: now})
+	} else {
+		// A packet sent again with the same key replaced the departure of
+		// the evicted one, which is kept then.
+		oldest := r.order[r.nextOrder]
+		if r.departures[oldest.key].Equal(oldest.time) {
+			delete(r.departures, oldest.key)
+		}
+		r.order[r.nextOrder] = departure{key: key, time: now}
+		r.nextOrder = (r.nextOrder + 1) % maxDepartures
+	}
+	r.departures[key] = now
+	r.sentBytes += size
+}
+
+func (r *flowRecorder) onReceived(now time.Time, header *rtp.Header, size int) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.receivedBytes += size
+	r.received++
+	key := departureKey{ssrc: header.SSRC, sequenceNumber: header.SequenceNumber}
+	if departure, ok := r.departures[key]; ok {
+		r.delaySum += now.Sub(departure)
+		r.matched++
+	}
+	highest, ok := r.highestSequence[header.SSRC]
+	switch {
+	case !ok:
+		r.highestSequence[header.SSRC] = header.SequenceNumber
+		r.expected++
+	case int16(header.SequenceNumber-highest) > 0: // nolint:gosec
+		r.expected += int(header.SequenceNumber - highest)
+		r.highestSequence[header.SSRC] = header.SequenceNumber
+	}
+}
+
+// sample returns the sample of the interval of length interval ending at now
+// and starts the next interval.
+func (r *flowRecorder) sample(now time.Time, interval time.Duration) Sample {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	sample := Sample{
+		Time:          now,
+		TargetBitrate: r.targetBitrate,
+		SendRate:      int(float64(8*r.sentBytes) / interval.Seconds()),
+		ReceiveRate:   int(float64(8*r.receivedBytes) / interval.Seconds()),
+		RTT:           r.rtt,
+		OneWayDelay:   0,
+		Loss:          0,
+	}
+	if r.matched > 0 {
+		sample.OneWayDelay = r.delaySum / time.Duration(r.matched)
+	}
+	if r.expected > r.received {
+		sample.Loss = float64(r.expected-r.received) / float64(r.expected)
+	}
+	r.sentBytes = 0
+	r.receivedBytes = 0
+	r.received = 0
+	r.delaySum = 0
+	r.matched = 0
+	r.expected = 0
+
+	return sample
+}
+
+// NewInterceptor returns an interceptor recording the RTP packets sent and
+// received by a peer of the flow. It implements interceptor.Factory.
+func (r *flowRecorder) NewInterceptor(_ string) (interceptor.Interceptor, error) {
+	return &recorderInterceptor{NoOp: interceptor.NoOp{}, recorder: r}, nil
+}
+
+type recorderInterceptor struct {
+	interceptor.NoOp
+	recorder *flowRecorder
+}
+
+// BindLocalStream implements interceptor.Interceptor.
+func (i *recorderInterceptor) BindLocalStream(
+	_ *interceptor.StreamInfo,
+	writer interceptor.RTPWriter,
+) interceptor.RTPWriter {
+	return interceptor.RTPWriterFunc(
+		func(header *rtp.Header, payload []byte, attributes interceptor.Attributes) (int, error) {
+			i.recorder.onSent(time.Now(), header, header.MarshalSize()+len(payload))
+
+			return writer.Write(header, payload, attributes)
+		},
+	)
+}
+
+// BindRemoteStream implements interceptor.Interceptor.
+func (i *recorderInterceptor) BindRemoteStream(
+	_ *interceptor.StreamInfo,
+	reader interceptor.RTPReader,
+) interceptor.RTPReader {
+	return interceptor.RTPReaderFunc(
+		func(b []byte, attributes interceptor.Attributes) (int, interceptor.Attributes, error) {
+			n, attributes, err := reader.Read(b, attributes)
+			if err != nil {
+				return n, attributes, err
+			}
+			if attributes == nil {
+				attributes = make(interceptor.Attributes)
+			}
+			header, err := attributes.GetRTPHeader(b[:n])
+			if err != nil {
+				return n, attributes, err
+			}
+			i.recorder.onReceived(time.Now(), header, n)
+
+			return n, attributes, nil
+		},
+	)
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js && go1.25
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pion/rtp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlowRecorderOneWayDelay(t *testing.T) {
+	r := newFlowRecorder(1_000_000)
+	start := time.Time{}.Add(time.Second)
+	ms := time.Millisecond
+
+	// Two streams use the same sequence number.
+	r.onSent(start, &rtp.Header{SSRC: 1, SequenceNumber: 7}, 1000)
+	r.onSent(start.Add(10*ms), &rtp.Header{SSRC: 2, SequenceNumber: 7}, 1000)
+	r.onReceived(start.Add(30*ms), &rtp.Header{SSRC: 1, SequenceNumber: 7}, 1000)
+	r.onReceived(start.Add(50*ms), &rtp.Header{SSRC: 2, SequenceNumber: 7}, 1000)
+	// A packet whose departure is unknown does not count towards the delay.
+	r.onReceived(start.Add(60*ms), &rtp.Header{SSRC: 3, SequenceNumber: 8}, 1000)
+
+	sample := r.sample(start.Add(100*ms), 100*ms)
+	assert.Equal(t, 35*ms, sample.OneWayDelay)
+	assert.Equal(t, 240_000, sample.ReceiveRate)
+}
+
+func TestFlowRecorderEvictsOldestDeparture(t *testing.T) {
+	r := newFlowRecorder(1_000_000)
+	start := time.Time{}.Add(time.Second)
+	for i := range maxDepartures + 1 {
+		header := &rtp.Header{SSRC: 1, SequenceNumber: uint16(i)} // nolint:gosec
+		r.onSent(start.Add(time.Duration(i)*time.Microsecond), header, 100)
+	}
+	assert.Len(t, r.departures, maxDepartures)
+
+	r.onReceived(start.Add(time.Second), &rtp.Header{SSRC: 1, SequenceNumber: 0}, 100)
+	assert.Zero(t, r.sample(start.Add(time.Second), time.Second).OneWayDelay)
+	r.onReceived(start.Add(time.Second), &rtp.Header{SSRC: 1, SequenceNumber: 1}, 100)
+	assert.Equal(t, time.Second-time.Microsecond, r.sample(start.Add(time.Second), time.Second).OneWayDelay)
+}
+
+func TestFlowRecorderLossPerSSRC(t *testing.T) {
+	r := newFlowRecorder(1_000_000)
+	start := time.Time{}.Add(time.Second)
+
+	// The sequence numbers of padding on an RTX stream are unrelated to those
+	// of the media stream.
+	for _, seq := range []uint16{17000, 17001, 17003} {
+		r.onReceived(start, &rtp.Header{SSRC: 1, SequenceNumber: seq}, 1000)
+		r.onReceived(start, &rtp.Header{SSRC: 2, SequenceNumber: seq - 16990}, 1000)
+	}
+
+	assert.InDelta(t, 2.0/8, r.sample(start.Add(time.Second), time.Second).Loss, 1e-9)
+}
@@ -0,0 +1,518 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pion/bwe/gcc"
+	"github.com/pion/bwe/simulation/internal/codec"
+	"github.com/pion/bwe/simulation/internal/peer"
+	"github.com/pion/logging"
+	"github.com/pion/transport/v3/vnet"
+	"github.com/pion/webrtc/v4"
+)
+
+const (
+	defaultSampleInterval = 100 * time.Millisecond
+	defaultPacingFactor   = 2.5
+
+	// maxFlows is the number of flows which fit into the address space of
+	// the virtual network.
+	maxFlows = 100
+
+	// connectTimeout is how long a scenario waits for its peers to connect.
+	connectTimeout = 10 * time.Second
+)
+
+var (
+	errInvalidScenario = errors.New("invalid scenario")
+	errConnectTimeout  = errors.New("peers did not connect")
+)
+
+// Scenario describes a simulation run declaratively. A scenario can be written
+// as Go struct or loaded from a YAML or JSON file with LoadScenario, and is
+// executed by RunScenario.
+type Scenario struct {
+	Name string
+	// Duration is how long the flows are observed after the peers were
+	// connected.
+	Duration time.Duration
+	// SampleInterval is the interval of the samples of each flow. The default
+	// is 100ms.
+	SampleInterval time.Duration
+	Topology       Topology
+	Flows          []Flow
+	// LogPackets logs every RTP and RTCP packet of every peer with slog.
+	LogPackets bool
+}
+
+// Topology is a dumbbell: all senders reach all receivers through the Forward
+// link, which is shared by all flows, and the feedback takes the Backward
+// link.
+type Topology struct {
+	Forward  LinkConfig
+	Backward LinkConfig
+}
+
+// Flow is a video stream from a sender to a receiver, produced by a codec
+// following the target bitrate of the controller exactly.
+type Flow struct {
+	Name string `yaml:"name"`
+	// Start is when the codec starts producing frames after the start of the
+	// scenario.
+	Start time.Duration `yaml:"start"`
+	// Stop is when the codec stops producing frames after the start of the
+	// scenario. 0 means at the end of the scenario.
+	Stop       time.Duration    `yaml:"stop"`
+	Controller ControllerConfig `yaml:"controller"`
+}
+
+// ControllerConfig configures the congestion controller of a flow.
+type ControllerConfig struct {
+	// InitialBitrate, MinBitrate and MaxBitrate are in bits per second. 0
+	// means the default of the gcc package.
+	InitialBitrate int `yaml:"initialBitrate"`
+	MinBitrate     int `yaml:"minBitrate"`
+	MaxBitrate     int `yaml:"maxBitrate"`
+	// DelayEstimator is "trendline", the default, or "kalman".
+	DelayEstimator string `yaml:"delayEstimator"`
+	// LossBasedBWEV2 enables the loss based estimator of libwebrtc.
+	LossBasedBWEV2 bool `yaml:"lossBasedBWEV2"`
+	// PacingFactor is the factor of the target bitrate at which packets are
+	// paced. The default is 2.5.
+	PacingFactor float64 `yaml:"pacingFactor"`
+	// Options are applied to the controller after the fields above. They
+	// cannot be loaded from a file.
+	Options []gcc.Option `yaml:"-"`
+}
+
+func (c ControllerConfig) controllerOptions() ([]gcc.Option, error) {
+	opts := []gcc.Option{}
+	if c.InitialBitrate != 0 {
+		opts = append(opts, gcc.WithInitialBitrate(c.InitialBitrate))
+	}
+	if c.MinBitrate != 0 {
+		opts = append(opts, gcc.WithMinBitrate(c.MinBitrate))
+	}
+	if c.MaxBitrate != 0 {
+		opts = append(opts, gcc.WithMaxBitrate(c.MaxBitrate))
+	}
+	switch c.DelayEstimator {
+	case "", "trendline":
+	case "kalman":
+		opts = append(opts, gcc.WithKalmanEstimator())
+	default:
+		return nil, fmt.Errorf("%w: unknown delay estimator %q", errInvalidScenario, c.DelayEstimator)
+	}
+	if c.LossBasedBWEV2 {
+		opts = append(opts, gcc.WithLossBasedBWEV2(true))
+	}
+
+	return append(opts, c.Options...), nil
+}
+
+func (s *Scenario) validate() error {
+	if s.Duration <= 0 {
+		return fmt.Errorf("%w: duration %v must be positive", errInvalidScenario, s.Duration)
+	}
+	if s.SampleInterval < 0 {
+		return fmt.Errorf("%w: sample interval %v must not be negative", errInvalidScenario, s.SampleInterval)
+	}
+	if len(s.Flows) == 0 || len(s.Flows) > maxFlows {
+		return fmt.Errorf("%w: %d flows, must be between 1 and %d", errInvalidScenario, len(s.Flows), maxFlows)
+	}
+	for i, flow := range s.Flows {
+		if flow.Start < 0 || flow.Stop < 0 || (flow.Stop != 0 && flow.Stop <= flow.Start) {
+			return fmt.Errorf(
+				"%w: flow %d starts at %v and stops at %v",
+				errInvalidScenario, i, flow.Start, flow.Stop,
+			)
+		}
+		if flow.Controller.PacingFactor < 0 {
+			return fmt.Errorf(
+				"%w: flow %d has negative pacing factor %v",
+				errInvalidScenario, i, flow.Controller.PacingFactor,
+			)
+		}
+		if _, err := flow.Controller.controllerOptions(); err != nil {
+			return err
+		}
+	}
+	if err := s.Topology.Forward.validate(); err != nil {
+		return fmt.Errorf("forward link: %w", err)
+	}
+	if err := s.Topology.Backward.validate(); err != nil {
+		return fmt.Errorf("backward link: %w", err)
+	}
+
+	return nil
+}
+
+// Result is the outcome of a scenario.
+type Result struct {
+	// Start is the time at which the scenario started.
+	Start time.Time
+	// Flows holds the samples of every flow in the order of the scenario.
+	Flows []FlowResult
+	// Capacity is the capacity of the forward link at the time of every
+	// sample.
+	Capacity []CapacitySample
+}
+
+// FlowResult holds the samples of a flow.
+type FlowResult struct {
+	Name    string
+	Samples []Sample
+}
+
+// RunScenario runs scenario and returns the samples of its flows. It must be
+// called in a testing/synctest bubble, so the scenario runs in virtual time
+// and its outcome only depends on the scenario.
+func RunScenario(scenario Scenario) (*Result, error) {
+	if err := scenario.validate(); err != nil {
+		return nil, err
+	}
+	if scenario.SampleInterval == 0 {
+		scenario.SampleInterval = defaultSampleInterval
+	}
+	run, err := newScenarioRun(scenario)
+	if err != nil {
+		return nil, err
+	}
+
+	return run.run()
+}
+
+// scenarioRun is the state of a running scenario.
+type scenarioRun struct {
+	scenario Scenario
+
+	wan      *vnet.Router
+	started  bool
+	forward  *Link
+	backward *Link
+	flows    []*flowRun
+
+	// lock protects closed, so no reader of a remote track is added to wg
+	// once close waits for them.
+	lock   sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+}
+
+// flowRun is the state of a running flow.
+type flowRun struct {
+	flow      Flow
+	recorder  *flowRecorder
+	sender    *peer.Peer
+	receiver  *peer.Peer
+	connected chan struct{}
+
+	// lock protects the codec, which is closed either when the flow stops or
+	// when the scenario is torn down.
+	lock        sync.Mutex
+	codec       *codec.Perfect
+	codecClosed bool
+}
+
+func (f *flowRun) setTargetBitrate(bitrate int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.recorder.onTargetBitrate(bitrate)
+	if f.codec != nil && !f.codecClosed {
+		f.codec.SetTargetBitrate(bitrate)
+	}
+}
+
+func (f *flowRun) startCodec() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.codec != nil && !f.codecClosed {
+		f.codec.Start()
+	}
+}
+
+func (f *flowRun) closeCodec() error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.codec == nil || f.codecClosed {
+		return nil
+	}
+	f.codecClosed = true
+
+	return f.codec.Close()
+}
+
+func newScenarioRun(scenario Scenario) (*scenarioRun, error) {
+	wan, err := vnet.NewRouter(&vnet.RouterConfig{
+		CIDR:          "10.0.0.0/24",
+		LoggerFactory: logging.NewDefaultLoggerFactory(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	forward, err := NewLink(scenario.Topology.Forward)
+	if err != nil {
+		return nil, err
+	}
+	backward, err := NewLink(scenario.Topology.Backward)
+	if err != nil {
+		return nil, err
+	}
+	run := &scenarioRun{
+		scenario: scenario,
+		wan:      wan,
+		started:  false,
+		forward:  forward,
+		backward: backward,
+		flows:    nil,
+		lock:     sync.Mutex{},
+		closed:   false,
+		done:     make(chan struct{}),
+		wg:       sync.WaitGroup{},
+	}
+	for i, flow := range scenario.Flows {
+		if flow.Name == "" {
+			flow.Name = fmt.Sprintf("flow-%d", i)
+		}
+		if err = run.addFlow(i, flow); err != nil {
+			return nil, errors.Join(err, run.close())
+		}
+	}
+	if err = wan.Start(); err != nil {
+		return nil, errors.Join(err, run.close())
+	}
+	run.started = true
+
+	return run, nil
+}
+
+// addFlow creates the peers of the flow with the index i. Its receiver gets
+// the address 10.0.0.(1+i) behind the forward link and its sender the address
+// 10.0.0.(128+i) behind the backward link.
+func (r *scenarioRun) addFlow(i int, flow Flow) error {
+	controllerOptions, err := flow.Controller.controllerOptions()
+	if err != nil {
+		return err
+	}
+	controller, err := gcc.NewController(controllerOptions...)
+	if err != nil {
+		return err
+	}
+	pacingFactor := flow.Controller.PacingFactor
+	if pacingFactor == 0 {
+		pacingFactor = defaultPacingFactor
+	}
+
+	receiverIP := fmt.Sprintf("10.0.0.%d", 1+i)
+	receiverNet, err := r.forward.AddNet(r.wan, receiverIP)
+	if err != nil {
+		return err
+	}
+	senderIP := fmt.Sprintf("10.0.0.%d", 128+i)
+	senderNet, err := r.backward.AddNet(r.wan, senderIP)
+	if err != nil {
+		return err
+	}
+
+	f := &flowRun{
+		flow:        flow,
+		recorder:    newFlowRecorder(controller.TargetBitrate()),
+		sender:      nil,
+		receiver:    nil,
+		connected:   make(chan struct{}),
+		lock:        sync.Mutex{},
+		codec:       nil,
+		codecClosed: false,
+	}
+	// The flow is added right away, so close tears down its peers if
+	// creating the rest fails.
+	r.flows = append(r.flows, f)
+	receiverOptions := []peer.Option{
+		peer.RegisterDefaultCodecs(),
+		peer.SetVNet(receiverNet, []string{receiverIP}),
+		peer.RegisterInterceptor(f.recorder),
+		peer.OnRemoteTrack(r.readTrack),
+		peer.RegisterCCFB(),
+	}
+	if r.scenario.LogPackets {
+		receiverOptions = append(receiverOptions, peer.RegisterPacketLogger(flow.Name+"-receiver"))
+	}
+	if f.receiver, err = peer.New(receiverOptions...); err != nil {
+		return err
+	}
+	if err = f.receiver.AddRemoteTrack(); err != nil {
+		return err
+	}
+
+	senderOptions := []peer.Option{
+		peer.RegisterDefaultCodecs(),
+		peer.OnConnected(sync.OnceFunc(func() { close(f.connected) })),
+		peer.SetVNet(senderNet, []string{senderIP}),
+		peer.RegisterInterceptor(f.recorder),
+	}
+	if r.scenario.LogPackets {
+		senderOptions = append(senderOptions, peer.RegisterPacketLogger(flow.Name+"-sender"))
+	}
+	senderOptions = append(senderOptions, peer.RegisterGCC(
+		gcc.WithControllerOptions(controllerOptions...),
+		gcc.OnTargetBitrateChange(f.setTargetBitrate),
+		gcc.OnRoundTripTime(f.recorder.onRTT),
+		// The codec writes whole frames at once, pace them like
+		// libwebrtc does.
+		gcc.WithPacing(pacingFactor),
+	))
+	if f.sender, err = peer.New(senderOptions...); err != nil {
+		return err
+	}
+	track, err := f.sender.AddLocalTrack()
+	if err != nil {
+		return err
+	}
+	f.lock.Lock()
+	f.codec = codec.NewPerfect(track, controller.TargetBitrate())
+	f.lock.Unlock()
+
+	return nil
+}
+
+// readTrack reads the remote track until the scenario is done.
+func (r *scenarioRun) readTrack(track *webrtc.TrackRemote) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.closed {
+		return
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		buf := make([]byte, 1500)
+		for {
+			select {
+			case <-r.done:
+				return
+			default:
+				if _, _, err := track.Read(buf); err != nil {
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (r *scenarioRun) run() (result *Result, err error) {
+	defer func() {
+		err = errors.Join(err, r.close())
+	}()
+	for _, f := range r.flows {
+		if err = signal(f.sender, f.receiver); err != nil {
+			return nil, err
+		}
+	}
+	timeout := time.After(connectTimeout)
+	for _, f := range r.flows {
+		select {
+		case <-f.connected:
+		case <-timeout:
+			return nil, fmt.Errorf("%w: %v", errConnectTimeout, f.flow.Name)
+		}
+	}
+
+	start := time.Now()
+	for _, f := range r.flows {
+		r.wg.Add(1)
+		go r.runCodec(start, f)
+	}
+	result = &Result{
+		Start:    start,
+		Flows:    make([]FlowResult, len(r.flows)),
+		Capacity: nil,
+	}
+	for i, f := range r.flows {
+		result.Flows[i].Name = f.flow.Name
+	}
+	ticker := time.NewTicker(r.scenario.SampleInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		for i, f := range r.flows {
+			sample := f.recorder.sample(now, r.scenario.SampleInterval)
+			result.Flows[i].Samples = append(result.Flows[i].Samples, sample)
+		}
+		result.Capacity = append(result.Capacity, CapacitySample{Time: now, Bandwidth: r.forward.Capacity(now)})
+		if now.Sub(start) >= r.scenario.Duration {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// runCodec starts the codec of f at its start and stops it at its stop or at
+// the end of the scenario.
+func (r *scenarioRun) runCodec(start time.Time, f *flowRun) {
+	defer r.wg.Done()
+	stop := f.flow.Stop
+	if stop == 0 {
+		stop = r.scenario.Duration
+	}
+	select {
+	case <-time.After(time.Until(start.Add(f.flow.Start))):
+	case <-r.done:
+		return
+	}
+	f.startCodec()
+	select {
+	case <-time.After(time.Until(start.Add(stop))):
+		_ = f.closeCodec()
+	case <-r.done:
+	}
+}
+
+// signal exchanges the offer of sender and the answer of receiver.
+func signal(sender, receiver *peer.Peer) error {
+	offer, err := sender.CreateOffer()
+	if err != nil {
+		return err
+	}
+	if err = receiver.SetRemoteDescription(offer); err != nil {
+		return err
+	}
+	answer, err := receiver.CreateAnswer()
+	if err != nil {
+		return err
+	}
+
+	return sender.SetRemoteDescription(answer)
+}
+
+// close stops the flows and tears down the network.
+func (r *scenarioRun) close() error {
+	r.lock.Lock()
+	r.closed = true
+	close(r.done)
+	r.lock.Unlock()
+	var err error
+	for _, f := range r.flows {
+		err = errors.Join(err, f.closeCodec())
+		if f.sender != nil {
+			err = errors.Join(err, f.sender.Close())
+		}
+		if f.receiver != nil {
+			err = errors.Join(err, f.receiver.Close())
+		}
+	}
+	r.wg.Wait()
+	if r.started {
+		err = errors.Join(err, r.wan.Stop())
+	}
+
+	return errors.Join(err, r.forward.Close(), r.backward.Close())
+}
@@ -0,0 +1,162 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js
+
+package simulation
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// scenarioFile is the layout of a scenario file. Durations are written like
+// "20ms" or "1m30s".
+type scenarioFile struct {
+	Name           string        `yaml:"name"`
+	Duration       time.Duration `yaml:"duration"`
+	SampleInterval time.Duration `yaml:"sampleInterval"`
+	Topology       struct {
+		Forward  linkFile `yaml:"forward"`
+		Backward linkFile `yaml:"backward"`
+	} `yaml:"topology"`
+	Flows      []Flow `yaml:"flows"`
+	LogPackets bool   `yaml:"logPackets"`
+}
+
+type linkFile struct {
+	Bandwidth   int             `yaml:"bandwidth"`
+	BurstSize   int             `yaml:"burstSize"`
+	QueueSize   int             `yaml:"queueSize"`
+	Queue       QueueDiscipline `yaml:"queue"`
+	RED         REDConfig       `yaml:"red"`
+	Delay       time.Duration   `yaml:"delay"`
+	RandomDelay time.Duration   `yaml:"randomDelay"`
+	Jitter      time.Duration   `yaml:"jitter"`
+	Loss        struct {
+		Bernoulli      float64 `yaml:"bernoulli"`
+		GilbertElliott *struct {
+			GoodToBad float64 `yaml:"goodToBad"`
+			BadToGood float64 `yaml:"badToGood"`
+			GoodLoss  float64 `yaml:"goodLoss"`
+			BadLoss   float64 `yaml:"badLoss"`
+		} `yaml:"gilbertElliott"`
+	} `yaml:"loss"`
+	Schedule Schedule `yaml:"schedule"`
+	// MahimahiTrace and PcapTrace are paths relative to the scenario file.
+	MahimahiTrace string `yaml:"mahimahiTrace"`
+	PcapTrace     string `yaml:"pcapTrace"`
+	Seed          uint64 `yaml:"seed"`
+}
+
+// LoadScenario reads a scenario from a YAML or JSON file at path. A scenario
+// with one flow over a link dropping from 2 Mbps to 500 kbps after 20 seconds
+// looks like this:
+//
+//	name: capacity-drop
+//	duration: 40s
+//	topology:
+//	  forward:
+//	    bandwidth: 2000000
+//	    queueSize: 60000
+//	    delay: 20ms
+//	    schedule:
+//	      - at: 20s
+//	        state: {bandwidth: 500000, delay: 20ms}
+//	  backward:
+//	    delay: 20ms
+//	flows:
+//	  - name: video
+//	    controller: {initialBitrate: 1000000}
+//
+// Links take their bandwidth from a trace instead if mahimahiTrace or
+// pcapTrace name a trace file relative to the scenario file. Losses are set by
+// either bernoulli, the loss probability, or gilbertElliott with goodToBad,
+// badToGood, goodLoss and badLoss. The queue is "drop-tail" or "red", with
+// minThreshold, maxThreshold, maxProbability and weight in red.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+	var file scenarioFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("%w: %w", errInvalidScenario, err)
+	}
+	dir := filepath.Dir(path)
+	forward, err := file.Topology.Forward.linkConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("forward link: %w", err)
+	}
+	backward, err := file.Topology.Backward.linkConfig(dir)
+	if err != nil {
+		return nil, fmt.Errorf("backward link: %w", err)
+	}
+	scenario := &Scenario{
+		Name:           file.Name,
+		Duration:       file.Duration,
+		SampleInterval: file.SampleInterval,
+		Topology:       Topology{Forward: forward, Backward: backward},
+		Flows:          file.Flows,
+		LogPackets:     file.LogPackets,
+	}
+	if err = scenario.validate(); err != nil {
+		return nil, err
+	}
+
+	return scenario, nil
+}
+
+func (l linkFile) linkConfig(dir string) (LinkConfig, error) {
+	config := LinkConfig{
+		Bandwidth:   l.Bandwidth,
+		BurstSize:   l.BurstSize,
+		QueueSize:   l.QueueSize,
+		Queue:       l.Queue,
+		RED:         l.RED,
+		Delay:       l.Delay,
+		RandomDelay: l.RandomDelay,
+		Jitter:      l.Jitter,
+		Loss:        nil,
+		Schedule:    l.Schedule,
+		Trace:       nil,
+		Seed:        l.Seed,
+	}
+	switch {
+	case l.Loss.Bernoulli != 0 && l.Loss.GilbertElliott != nil:
+		return config, fmt.Errorf("%w: bernoulli and gilbertElliott loss are exclusive", errInvalidLinkConfig)
+	case l.Loss.Bernoulli != 0:
+		config.Loss = NewBernoulliLoss(l.Loss.Bernoulli)
+	case l.Loss.GilbertElliott != nil:
+		ge := l.Loss.GilbertElliott
+		config.Loss = NewGilbertElliottLoss(ge.GoodToBad, ge.BadToGood, ge.GoodLoss, ge.BadLoss)
+	}
+	var err error
+	switch {
+	case l.MahimahiTrace != "" && l.PcapTrace != "":
+		return config, fmt.Errorf("%w: mahimahiTrace and pcapTrace are exclusive", errInvalidLinkConfig)
+	case l.MahimahiTrace != "":
+		config.Trace, err = loadTraceFile(filepath.Join(dir, l.MahimahiTrace), LoadMahimahiTrace)
+	case l.PcapTrace != "":
+		config.Trace, err = loadTraceFile(filepath.Join(dir, l.PcapTrace), LoadPcapTrace)
+	}
+
+	return config, err
+}
+
+func loadTraceFile(path string, load func(io.Reader) (*Trace, error)) (*Trace, error) {
+	data, err := os.ReadFile(path) // nolint:gosec
+	if err != nil {
+		return nil, err
+	}
+
+	return load(bytes.NewReader(data))
+}
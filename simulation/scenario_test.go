@@ -0,0 +1,305 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js && go1.25
+
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/synctest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// meanReceiveRate returns the mean receive rate of the samples from from on.
+func meanReceiveRate(samples []Sample, start time.Time, from time.Duration) int {
+	sum, count := 0, 0
+	for _, sample := range samples {
+		if sample.Time.Sub(start) > from {
+			sum += sample.ReceiveRate
+			count++
+		}
+	}
+	if count == 0 {
+		return 0
+	}
+
+	return sum / count
+}
+
+func TestRunScenario(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// The media is sent over a link slower than the initial bitrate.
+		result, err := RunScenario(Scenario{
+			Name:     "bottleneck",
+			Duration: 10 * time.Second,
+			Topology: Topology{
+				Forward: LinkConfig{
+					Bandwidth: 750_000,
+					QueueSize: 30_000,
+					Delay:     20 * time.Millisecond,
+					Seed:      1,
+				},
+				Backward: LinkConfig{Delay: 20 * time.Millisecond, Seed: 2},
+			},
+			Flows: []Flow{{
+				Name:       "video",
+				Controller: ControllerConfig{InitialBitrate: 1_000_000},
+			}},
+		})
+		assert.NoError(t, err)
+		synctest.Wait()
+
+		assert.Len(t, result.Flows, 1)
+		assert.Equal(t, "video", result.Flows[0].Name)
+		samples := result.Flows[0].Samples
+		assert.Len(t, samples, 100)
+		assert.Len(t, result.Capacity, 100)
+		for i, sample := range samples {
+			assert.Equal(t, result.Start.Add(time.Duration(i+1)*100*time.Millisecond), sample.Time)
+			assert.Equal(t, 750_000, result.Capacity[i].Bandwidth)
+			// The queue drains at most a burst faster than the bandwidth.
+			assert.LessOrEqual(t, sample.ReceiveRate, 900_000, "sample %v", i)
+			if sample.ReceiveRate > 0 {
+				assert.GreaterOrEqual(t, sample.OneWayDelay, 20*time.Millisecond, "sample %v", i)
+			}
+		}
+		last := samples[len(samples)-1]
+		assert.Less(t, last.TargetBitrate, 1_000_000)
+		// The round trip time is the propagation delay plus at most the full
+		// queue, but the feedback rounds arrival times to 1/1024s.
+		assert.GreaterOrEqual(t, last.RTT, 39*time.Millisecond)
+		assert.Less(t, last.RTT, 400*time.Millisecond)
+		assert.InDelta(t, 750_000, meanReceiveRate(samples, result.Start, 5*time.Second), 250_000)
+	})
+}
+
+func TestRunScenarioUnconstrained(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		// A single flow over links without any limits, logging every packet.
+		result, err := RunScenario(Scenario{
+			Name:       "unconstrained",
+			Duration:   10 * time.Second,
+			Flows:      []Flow{{Name: "video", Controller: ControllerConfig{InitialBitrate: 1_000_000}}},
+			LogPackets: true,
+		})
+		assert.NoError(t, err)
+		synctest.Wait()
+
+		samples := result.Flows[0].Samples
+		assert.Len(t, samples, 100)
+		for i, sample := range samples {
+			assert.Zero(t, result.Capacity[i].Bandwidth)
+			assert.Zero(t, sample.Loss, "sample %v", i)
+		}
+		// Without congestion, the target bitrate only grows.
+		assert.GreaterOrEqual(t, samples[len(samples)-1].TargetBitrate, 1_000_000)
+		assert.GreaterOrEqual(t, meanReceiveRate(samples, result.Start, 0), 900_000)
+	})
+}
+
+func TestRunScenarioFlowStartAndStop(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		result, err := RunScenario(Scenario{
+			Name:           "late",
+			Duration:       3 * time.Second,
+			SampleInterval: 500 * time.Millisecond,
+			Topology: Topology{
+				Forward:  LinkConfig{Bandwidth: 2_000_000, Delay: 10 * time.Millisecond},
+				Backward: LinkConfig{Delay: 10 * time.Millisecond},
+			},
+			Flows: []Flow{
+				{Name: "early", Stop: time.Second},
+				{Start: time.Second},
+			},
+		})
+		assert.NoError(t, err)
+		synctest.Wait()
+
+		assert.Equal(t, "early", result.Flows[0].Name)
+		assert.Equal(t, "flow-1", result.Flows[1].Name)
+		for i := range 6 {
+			early, late := result.Flows[0].Samples[i], result.Flows[1].Samples[i]
+			if i < 2 {
+				assert.Positive(t, early.SendRate, "sample %v", i)
+				assert.Zero(t, late.ReceiveRate, "sample %v", i)
+
+				continue
+			}
+			assert.Positive(t, late.ReceiveRate, "sample %v", i)
+			// The last frames of the early flow arrive in the first sample
+			// after it stopped, only padding may follow.
+			if i > 2 {
+				assert.Less(t, early.ReceiveRate, 50_000, "sample %v", i)
+			}
+		}
+	})
+}
+
+func TestScenarioValidate(t *testing.T) {
+	valid := func() Scenario {
+		return Scenario{Duration: time.Second, Flows: []Flow{{}}}
+	}
+	cases := []struct {
+		name   string
+		modify func(*Scenario)
+		valid  bool
+	}{
+		{name: "valid", modify: func(*Scenario) {}, valid: true},
+		{name: "noDuration", modify: func(s *Scenario) { s.Duration = 0 }, valid: false},
+		{name: "negativeSampleInterval", modify: func(s *Scenario) { s.SampleInterval = -1 }, valid: false},
+		{name: "noFlows", modify: func(s *Scenario) { s.Flows = nil }, valid: false},
+		{name: "tooManyFlows", modify: func(s *Scenario) { s.Flows = make([]Flow, 101) }, valid: false},
+		{
+			name:   "stopBeforeStart",
+			modify: func(s *Scenario) { s.Flows[0] = Flow{Start: time.Second, Stop: time.Millisecond} },
+			valid:  false,
+		},
+		{
+			name:   "unknownEstimator",
+			modify: func(s *Scenario) { s.Flows[0].Controller.DelayEstimator = "median" },
+			valid:  false,
+		},
+		{
+			name:   "negativePacingFactor",
+			modify: func(s *Scenario) { s.Flows[0].Controller.PacingFactor = -1 },
+			valid:  false,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			scenario := valid()
+			tc.modify(&scenario)
+			err := scenario.validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errInvalidScenario)
+			}
+		})
+	}
+
+	scenario := valid()
+	scenario.Topology.Forward.Bandwidth = -1
+	_, err := RunScenario(scenario)
+	assert.ErrorIs(t, err, errInvalidLinkConfig)
+}
+
+const yamlScenario = `
+name: capacity-drop
+duration: 40s
+sampleInterval: 200ms
+topology:
+  forward:
+    bandwidth: 2000000
+    queueSize: 60000
+    queue: red
+    red: {minThreshold: 10000, maxThreshold: 40000, maxProbability: 0.1, weight: 0.002}
+    delay: 20ms
+    jitter: 2ms
+    loss:
+      gilbertElliott: {goodToBad: 0.01, badToGood: 0.5, goodLoss: 0, badLoss: 0.5}
+    schedule:
+      - at: 20s
+        state: {bandwidth: 500000, delay: 20ms}
+      - at: 30s
+        state: {bandwidth: 3000000, delay: 20ms, loss: 0.01}
+        transition: ramp
+    seed: 7
+  backward:
+    mahimahiTrace: uplink.trace
+    delay: 20ms
+    loss: {bernoulli: 0.01}
+flows:
+  - name: video
+    controller: {initialBitrate: 1000000, maxBitrate: 2500000, delayEstimator: kalman}
+  - start: 10s
+    stop: 20s
+    controller: {lossBasedBWEV2: true, pacingFactor: 1.5}
+`
+
+const jsonScenario = `{
+  "name": "json",
+  "duration": "5s",
+  "topology": {"forward": {"bandwidth": 1000000}, "backward": {}},
+  "flows": [{"name": "video"}]
+}`
+
+func TestLoadScenario(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+		return path
+	}
+	write("uplink.trace", "1\n2\n3\n4\n")
+
+	scenario, err := LoadScenario(write("scenario.yaml", yamlScenario))
+	assert.NoError(t, err)
+	assert.Equal(t, "capacity-drop", scenario.Name)
+	assert.Equal(t, 40*time.Second, scenario.Duration)
+	assert.Equal(t, 200*time.Millisecond, scenario.SampleInterval)
+
+	forward := scenario.Topology.Forward
+	assert.Equal(t, 2_000_000, forward.Bandwidth)
+	assert.Equal(t, 60_000, forward.QueueSize)
+	assert.Equal(t, RED, forward.Queue)
+	assert.Equal(t, REDConfig{MinThreshold: 10_000, MaxThreshold: 40_000, MaxProbability: 0.1, Weight: 0.002}, forward.RED)
+	assert.Equal(t, 20*time.Millisecond, forward.Delay)
+	assert.Equal(t, 2*time.Millisecond, forward.Jitter)
+	assert.Equal(t, NewGilbertElliottLoss(0.01, 0.5, 0, 0.5), forward.Loss)
+	assert.Equal(t, Schedule{
+		{At: 20 * time.Second, State: LinkState{Bandwidth: 500_000, Delay: 20 * time.Millisecond}},
+		{
+			At:         30 * time.Second,
+			State:      LinkState{Bandwidth: 3_000_000, Delay: 20 * time.Millisecond, Loss: 0.01},
+			Transition: Ramp,
+		},
+	}, forward.Schedule)
+	assert.Equal(t, uint64(7), forward.Seed)
+
+	backward := scenario.Topology.Backward
+	assert.Equal(t, NewBernoulliLoss(0.01), backward.Loss)
+	assert.Len(t, backward.Trace.Opportunities, 4)
+	assert.Equal(t, 4*time.Millisecond, backward.Trace.Period)
+
+	assert.Equal(t, []Flow{
+		{
+			Name: "video",
+			Controller: ControllerConfig{
+				InitialBitrate: 1_000_000,
+				MaxBitrate:     2_500_000,
+				DelayEstimator: "kalman",
+			},
+		},
+		{
+			Start:      10 * time.Second,
+			Stop:       20 * time.Second,
+			Controller: ControllerConfig{LossBasedBWEV2: true, PacingFactor: 1.5},
+		},
+	}, scenario.Flows)
+
+	scenario, err = LoadScenario(write("scenario.json", jsonScenario))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, scenario.Duration)
+	assert.Equal(t, 1_000_000, scenario.Topology.Forward.Bandwidth)
+	assert.Equal(t, "video", scenario.Flows[0].Name)
+
+	for name, content := range map[string]string{
+		"unknownField":   "duration: 1s\nflows: [{}]\nbandwidth: 1\n",
+		"unknownQueue":   "duration: 1s\nflows: [{}]\ntopology: {forward: {queue: fifo}}\n",
+		"noFlows":        "duration: 1s\n",
+		"bothLossModels": "duration: 1s\nflows: [{}]\ntopology: {forward: {loss: {bernoulli: 0.1, gilbertElliott: {}}}}\n",
+		"missingTrace":   "duration: 1s\nflows: [{}]\ntopology: {forward: {mahimahiTrace: missing.trace}}\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := LoadScenario(write(name+".yaml", content))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// LinkState is the capacity, delay and random loss of a link.
+type LinkState struct {
+	// Bandwidth is the capacity of the link in bits per second. 0 means
+	// unlimited.
+	Bandwidth int `yaml:"bandwidth"`
+	// Delay is the fixed one way delay of the link.
+	Delay time.Duration `yaml:"delay"`
+	// Loss is the probability of a packet to be dropped independently of the
+	// queue, in addition to the loss model of the link.
+	Loss float64 `yaml:"loss"`
+}
+
+// Transition selects how a link changes to the state of a ScheduleEntry.
+type Transition int
+
+const (
+	// Step changes to the state at the time of the entry.
+	Step Transition = iota
+	// Ramp changes linearly from the state of the previous entry, starting
+	// at the time of the previous entry, to the state at the time of the
+	// entry.
+	Ramp
+)
+
+func (t Transition) String() string {
+	switch t {
+	case Step:
+		return "step"
+	case Ramp:
+		return "ramp"
+	default:
+		return fmt.Sprintf("invalid transition: %d", t)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, so transitions are
+// written as "step" or "ramp" in scenario files.
+func (t *Transition) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "step":
+		*t = Step
+	case "ramp":
+		*t = Ramp
+	default:
+		return fmt.Errorf("%w: unknown transition %q", errInvalidLinkConfig, text)
+	}
+
+	return nil
+}
+
+// ScheduleEntry is a state a link reaches At after the link was created.
+type ScheduleEntry struct {
+	At         time.Duration `yaml:"at"`
+	State      LinkState     `yaml:"state"`
+	Transition Transition    `yaml:"transition"`
+}
+
+// Schedule changes the state of a link over time, e.g. to drop the capacity
+// from 2 Mbps to 500 kbps after 20 seconds and raise it to 3 Mbps after 40
+// seconds:
+//
+//	Schedule{
+//		{At: 0, State: LinkState{Bandwidth: 2_000_000}},
+//		{At: 20 * time.Second, State: LinkState{Bandwidth: 500_000}},
+//		{At: 40 * time.Second, State: LinkState{Bandwidth: 3_000_000}},
+//	}
+//
+// Before the first entry, the link has the bandwidth and delay of its
+// LinkConfig and no additional loss. Entries must be sorted by At.
+type Schedule []ScheduleEntry
+
+func (s Schedule) validate() error {
+	for i, entry := range s {
+		if entry.At < 0 || (i > 0 && entry.At < s[i-1].At) {
+			return fmt.Errorf("%w: schedule entry %d at %v is out of order", errInvalidLinkConfig, i, entry.At)
+		}
+		if entry.State.Bandwidth < 0 || entry.State.Delay < 0 {
+			return fmt.Errorf(
+				"%w: schedule entry %d has negative bandwidth %d or delay %v",
+				errInvalidLinkConfig, i, entry.State.Bandwidth, entry.State.Delay,
+			)
+		}
+		if entry.State.Loss < 0 || entry.State.Loss > 1 {
+			return fmt.Errorf("%w: schedule entry %d has loss %v outside of [0, 1]", errInvalidLinkConfig, i, entry.State.Loss)
+		}
+		if entry.Transition != Step && entry.Transition != Ramp {
+			return fmt.Errorf("%w: schedule entry %d has unknown transition %v", errInvalidLinkConfig, i, entry.Transition)
+		}
+	}
+
+	return nil
+}
+
+// stateAt returns the state elapsed after the link was created, starting from
+// initial.
+func (s Schedule) stateAt(initial LinkState, elapsed time.Duration) LinkState {
+	state := initial
+	from := time.Duration(0)
+	for _, entry := range s {
+		if elapsed >= entry.At {
+			state = entry.State
+			from = entry.At
+
+			continue
+		}
+		if entry.Transition == Ramp {
+			progress := float64(elapsed-from) / float64(entry.At-from)
+			state = LinkState{
+				Bandwidth: state.Bandwidth + int(progress*float64(entry.State.Bandwidth-state.Bandwidth)),
+				Delay:     state.Delay + time.Duration(progress*float64(entry.State.Delay-state.Delay)),
+				Loss:      state.Loss + progress*(entry.State.Loss-state.Loss),
+			}
+		}
+
+		break
+	}
+
+	return state
+}
+
+// CapacitySample is the capacity of a link from Time on.
+type CapacitySample struct {
+	Time      time.Time
+	Bandwidth int
+}
@@ -0,0 +1,137 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js && go1.25
+
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduleStateAt(t *testing.T) {
+	initial := LinkState{Bandwidth: 1_000_000, Delay: 10 * time.Millisecond}
+	schedule := Schedule{
+		{At: 10 * time.Second, State: LinkState{Bandwidth: 500_000, Delay: 10 * time.Millisecond}},
+		{
+			At:         20 * time.Second,
+			State:      LinkState{Bandwidth: 1_500_000, Delay: 30 * time.Millisecond, Loss: 0.1},
+			Transition: Ramp,
+		},
+	}
+	cases := []struct {
+		elapsed  time.Duration
+		expected LinkState
+	}{
+		{elapsed: 0, expected: initial},
+		{elapsed: 10*time.Second - 1, expected: initial},
+		{elapsed: 10 * time.Second, expected: LinkState{Bandwidth: 500_000, Delay: 10 * time.Millisecond}},
+		{elapsed: 15 * time.Second, expected: LinkState{Bandwidth: 1_000_000, Delay: 20 * time.Millisecond, Loss: 0.05}},
+		{elapsed: 20 * time.Second, expected: LinkState{Bandwidth: 1_500_000, Delay: 30 * time.Millisecond, Loss: 0.1}},
+		{elapsed: time.Minute, expected: LinkState{Bandwidth: 1_500_000, Delay: 30 * time.Millisecond, Loss: 0.1}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.elapsed.String(), func(t *testing.T) {
+			state := schedule.stateAt(initial, tc.elapsed)
+			assert.Equal(t, tc.expected.Bandwidth, state.Bandwidth)
+			assert.Equal(t, tc.expected.Delay, state.Delay)
+			assert.InDelta(t, tc.expected.Loss, state.Loss, 1e-9)
+		})
+	}
+
+	// A ramp in the first entry starts from the initial state.
+	ramp := Schedule{{At: 10 * time.Second, State: LinkState{Bandwidth: 2_000_000}, Transition: Ramp}}
+	assert.Equal(t, 1_500_000, ramp.stateAt(initial, 5*time.Second).Bandwidth)
+}
+
+func TestScheduleValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		schedule Schedule
+		valid    bool
+	}{
+		{name: "empty", schedule: nil, valid: true},
+		{
+			name: "sorted",
+			schedule: Schedule{
+				{At: 0, State: LinkState{Bandwidth: 2_000_000}},
+				{At: time.Second, State: LinkState{Bandwidth: 500_000, Loss: 1}, Transition: Ramp},
+			},
+			valid: true,
+		},
+		{name: "negativeAt", schedule: Schedule{{At: -1}}, valid: false},
+		{name: "unsorted", schedule: Schedule{{At: time.Second}, {At: 0}}, valid: false},
+		{name: "negativeBandwidth", schedule: Schedule{{State: LinkState{Bandwidth: -1}}}, valid: false},
+		{name: "negativeDelay", schedule: Schedule{{State: LinkState{Delay: -1}}}, valid: false},
+		{name: "lossAboveOne", schedule: Schedule{{State: LinkState{Loss: 1.5}}}, valid: false},
+		{name: "unknownTransition", schedule: Schedule{{Transition: 7}}, valid: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := LinkConfig{Schedule: tc.schedule}.validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, errInvalidLinkConfig)
+			}
+		})
+	}
+}
+
+func TestLinkModelSchedule(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	m, err := newLinkModel(LinkConfig{
+		Bandwidth: 1_000_000,
+		Schedule: Schedule{
+			{At: time.Second, State: LinkState{Bandwidth: 500_000, Delay: 20 * time.Millisecond}},
+			{At: 2 * time.Second, State: LinkState{Bandwidth: 500_000, Loss: 1}},
+		},
+	}, start)
+	assert.NoError(t, err)
+
+	// 1250 bytes every 10ms fill 1 Mbps, so no queue builds up.
+	deliveries := sendConstantRate(t, m, start, 100, 1250, 10*time.Millisecond)
+	for i, delivery := range deliveries {
+		assert.Equal(t, start.Add(time.Duration(i)*10*time.Millisecond), delivery, "packet %v", i)
+	}
+
+	// At 500 kbps the packets leave the queue 20ms apart, plus the delay. The
+	// first waits 6ms for the 375 bytes missing in the bucket.
+	deliveries = sendConstantRate(t, m, start.Add(time.Second), 10, 1250, 10*time.Millisecond)
+	assert.Equal(t, start.Add(time.Second+26*time.Millisecond), deliveries[0])
+	for i := 1; i < len(deliveries); i++ {
+		assert.Equal(t, 20*time.Millisecond, deliveries[i].Sub(deliveries[i-1]), "packet %v", i)
+	}
+
+	deliveries = sendConstantRate(t, m, start.Add(2*time.Second), 10, 1250, 10*time.Millisecond)
+	for _, delivery := range deliveries {
+		assert.True(t, delivery.IsZero())
+	}
+
+	assert.Equal(t, []CapacitySample{
+		{Time: start, Bandwidth: 1_000_000},
+		{Time: start.Add(time.Second), Bandwidth: 500_000},
+	}, m.capacity)
+}
+
+func TestLinkCapacity(t *testing.T) {
+	link, err := NewLink(LinkConfig{
+		Bandwidth: 2_000_000,
+		Schedule: Schedule{
+			{At: 20 * time.Second, State: LinkState{Bandwidth: 500_000}},
+			{At: 40 * time.Second, State: LinkState{Bandwidth: 3_000_000}, Transition: Ramp},
+		},
+	})
+	assert.NoError(t, err)
+	start := link.model.start
+
+	assert.Equal(t, 2_000_000, link.Capacity(start.Add(10*time.Second)))
+	assert.Equal(t, 500_000, link.Capacity(start.Add(20*time.Second)))
+	assert.Equal(t, 1_750_000, link.Capacity(start.Add(30*time.Second)))
+	assert.Equal(t, 3_000_000, link.Capacity(start.Add(time.Minute)))
+	assert.Equal(t, []CapacitySample{{Time: start, Bandwidth: 2_000_000}}, link.CapacityLog())
+	assert.NoError(t, link.Close())
+}
@@ -2,5 +2,7 @@
 // SPDX-License-Identifier: MIT
 
 // Package simulation implements bandwidth estimation tests using the synctest
-// package.
+// package. RunScenario runs flows over emulated links described by a Scenario
+// in virtual time, and Result.Metrics evaluates the outcome by the criteria of
+// RFC 8868 against Thresholds.
 package simulation
@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+package simulation
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// mahimahiOpportunitySize is the number of bytes a link can send in one
+	// delivery opportunity of a Mahimahi trace.
+	mahimahiOpportunitySize = 1500
+
+	pcapMagicMicros      = 0xa1b2c3d4
+	pcapMagicNanos       = 0xa1b23c4d
+	pcapFileHeaderSize   = 24
+	pcapRecordHeaderSize = 16
+)
+
+var (
+	errInvalidTrace    = errors.New("invalid trace")
+	errUnsupportedPcap = errors.New("unsupported pcap file")
+)
+
+// DeliveryOpportunity is a point in time at which a link can send Size bytes.
+type DeliveryOpportunity struct {
+	At   time.Duration
+	Size int
+}
+
+// Trace describes the capacity of a link by the delivery opportunities it
+// has. A link replaying a trace sends the queued bytes at the opportunities
+// and wastes opportunities while its queue is empty. The trace repeats every
+// Period, so a link replays it for as long as it exists.
+type Trace struct {
+	// Opportunities are the delivery opportunities sorted by At, which must
+	// not exceed Period.
+	Opportunities []DeliveryOpportunity
+	// Period is the time after which the trace repeats.
+	Period time.Duration
+}
+
+// LoadMahimahiTrace reads a trace in the format of the Mahimahi link shell,
+// where every line holds the millisecond at which the link can send a packet
+// of up to 1500 bytes. The trace repeats after the last timestamp.
+func LoadMahimahiTrace(r io.Reader) (*Trace, error) {
+	trace := &Trace{Opportunities: nil, Period: 0}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		ms, err := strconv.ParseUint(text, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", errInvalidTrace, line, err)
+		}
+		at := time.Duration(ms) * time.Millisecond
+		if at < trace.Period {
+			return nil, fmt.Errorf("%w: line %d: timestamp %d is before its predecessor", errInvalidTrace, line, ms)
+		}
+		trace.Opportunities = append(trace.Opportunities, DeliveryOpportunity{
+			At:   at,
+			Size: mahimahiOpportunitySize,
+		})
+		trace.Period = at
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := trace.validate(); err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
+
+// LoadPcapTrace reads a trace from a pcap file captured behind the bottleneck
+// of a real link, e.g. at a phone downloading as fast as it can. Every captured
+// packet becomes a delivery opportunity of its original length at its capture
+// time relative to the first packet. The trace repeats one average gap between
+// packets after the last packet. Only the classic pcap format is supported,
+// not pcapng.
+func LoadPcapTrace(r io.Reader) (*Trace, error) {
+	header := make([]byte, pcapFileHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnsupportedPcap, err)
+	}
+	// The magic number is written in the byte order of the capturing host and
+	// tells the resolution of the timestamps.
+	var order binary.ByteOrder = binary.BigEndian
+	if magic := order.Uint32(header); magic != pcapMagicMicros && magic != pcapMagicNanos {
+		order = binary.LittleEndian
+	}
+	var unit time.Duration
+	switch magic := order.Uint32(header); magic {
+	case pcapMagicMicros:
+		unit = time.Microsecond
+	case pcapMagicNanos:
+		unit = time.Nanosecond
+	default:
+		return nil, fmt.Errorf("%w: unknown magic number %x", errUnsupportedPcap, magic)
+	}
+
+	trace := &Trace{Opportunities: nil, Period: 0}
+	var first time.Time
+	record := make([]byte, pcapRecordHeaderSize)
+	for {
+		if _, err := io.ReadFull(r, record); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: %w", errUnsupportedPcap, err)
+		}
+		captured := time.Unix(
+			int64(order.Uint32(record[0:4])),
+			int64(time.Duration(order.Uint32(record[4:8]))*unit),
+		)
+		capturedLength := order.Uint32(record[8:12])
+		originalLength := order.Uint32(record[12:16])
+		if _, err := io.CopyN(io.Discard, r, int64(capturedLength)); err != nil {
+			return nil, fmt.Errorf("%w: %w", errUnsupportedPcap, err)
+		}
+		if len(trace.Opportunities) == 0 {
+			first = captured
+		}
+		at := captured.Sub(first)
+		if at < trace.Period {
+			return nil, fmt.Errorf(
+				"%w: packet %d was captured before its predecessor",
+				errInvalidTrace, len(trace.Opportunities),
+			)
+		}
+		trace.Opportunities = append(trace.Opportunities, DeliveryOpportunity{
+			At:   at,
+			Size: int(originalLength),
+		})
+		trace.Period = at
+	}
+	if len(trace.Opportunities) > 1 {
+		trace.Period += trace.Period / time.Duration(len(trace.Opportunities)-1)
+	}
+	if err := trace.validate(); err != nil {
+		return nil, err
+	}
+
+	return trace, nil
+}
+
+func (t *Trace) validate() error {
+	if len(t.Opportunities) == 0 {
+		return fmt.Errorf("%w: no delivery opportunities", errInvalidTrace)
+	}
+	if t.Period <= 0 {
+		return fmt.Errorf("%w: period %v must be positive", errInvalidTrace, t.Period)
+	}
+	for i, opportunity := range t.Opportunities {
+		if opportunity.At < 0 || opportunity.At > t.Period || (i > 0 && opportunity.At < t.Opportunities[i-1].At) {
+			return fmt.Errorf(
+				"%w: delivery opportunity %d at %v is out of order or after the period",
+				errInvalidTrace, i, opportunity.At,
+			)
+		}
+		if opportunity.Size <= 0 {
+			return fmt.Errorf("%w: delivery opportunity %d has size %d", errInvalidTrace, i, opportunity.Size)
+		}
+	}
+
+	return nil
+}
+
+// opportunityAt returns the time after the start of the trace and the size of
+// the delivery opportunity with the index i, counting on into the repetitions
+// of the trace.
+func (t *Trace) opportunityAt(i int) (time.Duration, int) {
+	opportunity := t.Opportunities[i%len(t.Opportunities)]
+
+	return time.Duration(i/len(t.Opportunities))*t.Period + opportunity.At, opportunity.Size
+}
+
+// bandwidth returns the capacity of the trace in bits per second between from
+// and to after its start.
+func (t *Trace) bandwidth(from, to time.Duration) int {
+	from = max(from, 0)
+	if to <= from {
+		return 0
+	}
+	bytes := 0
+	for i := int(from/t.Period) * len(t.Opportunities); ; i++ {
+		at, size := t.opportunityAt(i)
+		if at >= to {
+			break
+		}
+		if at >= from {
+			bytes += size
+		}
+	}
+
+	return int(float64(bytes*8) / (to - from).Seconds())
+}
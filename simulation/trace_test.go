@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2026 The Pion community <https://pion.ly>
+// SPDX-License-Identifier: MIT
+
+//go:build !js && go1.25
+
+package simulation
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMahimahiTrace(t *testing.T) {
+	trace, err := LoadMahimahiTrace(strings.NewReader("1\n2\n2\n\n4\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, &Trace{
+		Opportunities: []DeliveryOpportunity{
+			{At: time.Millisecond, Size: 1500},
+			{At: 2 * time.Millisecond, Size: 1500},
+			{At: 2 * time.Millisecond, Size: 1500},
+			{At: 4 * time.Millisecond, Size: 1500},
+		},
+		Period: 4 * time.Millisecond,
+	}, trace)
+
+	for _, invalid := range []string{"", "0\n", "1\nx\n", "5\n3\n", "-1\n"} {
+		trace, err = LoadMahimahiTrace(strings.NewReader(invalid))
+		assert.ErrorIs(t, err, errInvalidTrace, "trace %q", invalid)
+		assert.Nil(t, trace)
+	}
+}
+
+type pcapRecord struct {
+	captured time.Time
+	length   int
+}
+
+// writePcap returns a pcap file with the records in the byte order, whose
+// captured packets are truncated to 64 bytes.
+func writePcap(t *testing.T, order binary.ByteOrder, magic uint32, records []pcapRecord) []byte {
+	t.Helper()
+
+	buf := &bytes.Buffer{}
+	header := make([]byte, pcapFileHeaderSize)
+	order.PutUint32(header[0:4], magic)
+	order.PutUint16(header[4:6], 2)
+	order.PutUint16(header[6:8], 4)
+	order.PutUint32(header[16:20], 64)
+	order.PutUint32(header[20:24], 1)
+	buf.Write(header)
+	for _, r := range records {
+		record := make([]byte, pcapRecordHeaderSize)
+		fraction := r.captured.Nanosecond()
+		if magic == pcapMagicMicros {
+			fraction /= 1000
+		}
+		captured := min(r.length, 64)
+		order.PutUint32(record[0:4], uint32(r.captured.Unix())) // nolint:gosec
+		order.PutUint32(record[4:8], uint32(fraction))          // nolint:gosec
+		order.PutUint32(record[8:12], uint32(captured))         // nolint:gosec
+		order.PutUint32(record[12:16], uint32(r.length))        // nolint:gosec
+		buf.Write(record)
+		buf.Write(make([]byte, captured))
+	}
+
+	return buf.Bytes()
+}
+
+func TestLoadPcapTrace(t *testing.T) {
+	first := time.Unix(1_700_000_000, 500_000_000)
+	records := []pcapRecord{
+		{captured: first, length: 1000},
+		{captured: first.Add(time.Millisecond), length: 1500},
+		{captured: first.Add(3 * time.Millisecond), length: 40},
+	}
+	expected := &Trace{
+		Opportunities: []DeliveryOpportunity{
+			{At: 0, Size: 1000},
+			{At: time.Millisecond, Size: 1500},
+			{At: 3 * time.Millisecond, Size: 40},
+		},
+		Period: 4500 * time.Microsecond,
+	}
+	cases := []struct {
+		name  string
+		order binary.ByteOrder
+		magic uint32
+	}{
+		{name: "littleEndianMicros", order: binary.LittleEndian, magic: pcapMagicMicros},
+		{name: "bigEndianMicros", order: binary.BigEndian, magic: pcapMagicMicros},
+		{name: "littleEndianNanos", order: binary.LittleEndian, magic: pcapMagicNanos},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			trace, err := LoadPcapTrace(bytes.NewReader(writePcap(t, tc.order, tc.magic, records)))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, trace)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		file := writePcap(t, binary.LittleEndian, pcapMagicMicros, records)
+		_, err := LoadPcapTrace(bytes.NewReader(file[:10]))
+		assert.ErrorIs(t, err, errUnsupportedPcap)
+		_, err = LoadPcapTrace(bytes.NewReader(file[:len(file)-1]))
+		assert.ErrorIs(t, err, errUnsupportedPcap)
+		_, err = LoadPcapTrace(bytes.NewReader(writePcap(t, binary.LittleEndian, 0x0a0d0d0a, records)))
+		assert.ErrorIs(t, err, errUnsupportedPcap)
+		_, err = LoadPcapTrace(bytes.NewReader(writePcap(t, binary.LittleEndian, pcapMagicMicros, records[:1])))
+		assert.ErrorIs(t, err, errInvalidTrace)
+		reordered := []pcapRecord{records[1], records[0]}
+		_, err = LoadPcapTrace(bytes.NewReader(writePcap(t, binary.LittleEndian, pcapMagicMicros, reordered)))
+		assert.ErrorIs(t, err, errInvalidTrace)
+	})
+}
+
+// everyMillisecond returns a trace with an opportunity of 1500 bytes every
+// millisecond, i.e. 12 Mbps, which repeats every 10ms.
+func everyMillisecond() *Trace {
+	trace := &Trace{Opportunities: nil, Period: 10 * time.Millisecond}
+	for i := 1; i <= 10; i++ {
+		trace.Opportunities = append(trace.Opportunities, DeliveryOpportunity{
+			At:   time.Duration(i) * time.Millisecond,
+			Size: 1500,
+		})
+	}
+
+	return trace
+}
+
+func TestLinkModelTrace(t *testing.T) {
+	start := time.Time{}.Add(time.Second)
+	m, err := newLinkModel(LinkConfig{Trace: everyMillisecond(), Delay: 5 * time.Millisecond}, start)
+	assert.NoError(t, err)
+	at := func(d time.Duration) time.Time {
+		return start.Add(d)
+	}
+
+	// Packets sent at once depart at consecutive opportunities.
+	assert.Equal(t,
+		[]time.Time{at(6 * time.Millisecond), at(7 * time.Millisecond), at(8 * time.Millisecond)},
+		sendConstantRate(t, m, start, 3, 1500, 0),
+	)
+	// Two small packets share an opportunity.
+	assert.Equal(t,
+		[]time.Time{at(9 * time.Millisecond), at(9 * time.Millisecond), at(10 * time.Millisecond)},
+		sendConstantRate(t, m, at(3500*time.Microsecond), 3, 750, 0),
+	)
+	// The free bytes of the opportunity at 5ms are wasted, a large packet
+	// departs with its last byte, and the trace repeats.
+	assert.Equal(t,
+		[]time.Time{at(15 * time.Millisecond), at(17 * time.Millisecond)},
+		sendConstantRate(t, m, at(8500*time.Microsecond), 2, 3000, 0),
+	)
+	// Opportunities are wasted while the link is idle.
+	assert.Equal(t,
+		[]time.Time{at(1005 * time.Millisecond)},
+		sendConstantRate(t, m, at(999500*time.Microsecond), 1, 1500, 0),
+	)
+	assert.Empty(t, m.capacity)
+}
+
+func TestLinkTraceCapacity(t *testing.T) {
+	link, err := NewLink(LinkConfig{Trace: everyMillisecond()})
+	assert.NoError(t, err)
+	start := link.model.start
+
+	assert.Equal(t, 12_000_000, link.Capacity(start.Add(500*time.Microsecond)))
+	assert.Equal(t, 12_000_000, link.Capacity(start.Add(time.Minute+500*time.Microsecond)))
+	assert.Empty(t, link.CapacityLog())
+	assert.NoError(t, link.Close())
+
+	for _, config := range []LinkConfig{
+		{Trace: everyMillisecond(), Bandwidth: 1_000_000},
+		{Trace: everyMillisecond(), Schedule: Schedule{{At: time.Second, State: LinkState{Bandwidth: 1}}}},
+		{Trace: &Trace{Opportunities: nil, Period: time.Second}},
+		{Trace: &Trace{Opportunities: []DeliveryOpportunity{{At: 2 * time.Second, Size: 1}}, Period: time.Second}},
+		{Trace: &Trace{Opportunities: []DeliveryOpportunity{{At: 0, Size: 0}}, Period: time.Second}},
+	} {
+		link, err = NewLink(config)
+		assert.ErrorIs(t, err, errInvalidLinkConfig)
+		assert.Nil(t, link)
+	}
+}
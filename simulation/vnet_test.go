@@ -8,10 +8,15 @@ package simulation
 import (
 	"errors"
 	"io"
+	"log/slog"
+	"sync"
 	"testing"
 	"testing/synctest"
 	"time"
 
+	"github.com/pion/bwe/gcc"
+	"github.com/pion/bwe/simulation/internal/codec"
+	"github.com/pion/bwe/simulation/internal/peer"
 	"github.com/pion/logging"
 	"github.com/pion/transport/v3/vnet"
 	"github.com/pion/webrtc/v4"
@@ -19,16 +24,21 @@ import (
 )
 
 type network struct {
-	wan   *vnet.Router
-	left  *vnet.Net
-	right *vnet.Net
+	wan       *vnet.Router
+	left      *vnet.Net
+	right     *vnet.Net
+	leftLink  *Link
+	rightLink *Link
 }
 
 func (n *network) Close() error {
-	return n.wan.Stop()
+	return errors.Join(n.wan.Stop(), n.leftLink.Close(), n.rightLink.Close())
 }
 
-func createVirtualNetwork(t *testing.T) *network {
+// createVirtualNetwork creates two Nets behind NATs, where packets towards the
+// left Net pass a link configured by toLeft and packets towards the right Net
+// one configured by toRight.
+func createVirtualNetwork(t *testing.T, toLeft, toRight LinkConfig) *network {
 	t.Helper()
 
 	wan, err := vnet.NewRouter(&vnet.RouterConfig{
@@ -68,42 +78,81 @@ func createVirtualNetwork(t *testing.T) *network {
 	err = wan.Start()
 	assert.NoError(t, err)
 
-	leftNet, err := vnet.NewNet(&vnet.NetConfig{
-		StaticIPs: []string{"10.0.1.101"},
-		StaticIP:  "",
-	})
+	leftLink, err := NewLink(toLeft)
 	assert.NoError(t, err)
-	err = leftRouter.AddNet(leftNet)
+	leftNet, err := leftLink.AddNet(leftRouter, "10.0.1.101")
 	assert.NoError(t, err)
 
-	rightNet, err := vnet.NewNet(&vnet.NetConfig{
-		StaticIPs: []string{"10.0.2.101"},
-		StaticIP:  "",
-	})
+	rightLink, err := NewLink(toRight)
 	assert.NoError(t, err)
-	err = rightRouter.AddNet(rightNet)
+	rightNet, err := rightLink.AddNet(rightRouter, "10.0.2.101")
 	assert.NoError(t, err)
 
 	return &network{
-		wan:   wan,
-		left:  leftNet,
-		right: rightNet,
+		wan:       wan,
+		left:      leftNet,
+		right:     rightNet,
+		leftLink:  leftLink,
+		rightLink: rightLink,
 	}
 }
 
 func TestVnet(t *testing.T) {
+	cases := []struct {
+		name     string
+		receiver []peer.Option
+		sender   []peer.Option
+	}{
+		{
+			name:     "ccfb",
+			receiver: []peer.Option{peer.RegisterCCFB()},
+			sender:   []peer.Option{},
+		},
+		{
+			// The GCC interceptor sets the transport wide sequence numbers
+			// of the packets it sends.
+			name:     "twcc",
+			receiver: []peer.Option{peer.RegisterTWCCHeaderExtension(), peer.RegisterTWCC()},
+			sender:   []peer.Option{peer.RegisterTWCCHeaderExtension()},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			testVnet(t, tc.receiver, tc.sender)
+		})
+	}
+}
+
+// testVnet sends media from a sender configured by senderOptions to a receiver
+// configured by receiverOptions and checks that GCC uses the feedback of the
+// receiver.
+func testVnet(t *testing.T, receiverOptions, senderOptions []peer.Option) {
+	t.Helper()
+
 	synctest.Test(t, func(t *testing.T) {
 		t.Helper()
 
 		onTrack := make(chan struct{})
 		connected := make(chan struct{})
 		done := make(chan struct{})
-
-		network := createVirtualNetwork(t)
-		receiver, err := newPeer(
-			registerDefaultCodecs(),
-			setVNet(network.left, []string{"10.0.1.1"}),
-			onRemoteTrack(func(track *webrtc.TrackRemote) {
+		var rttLock sync.Mutex
+		var rtt time.Duration
+
+		// The media is sent from the right to the left over a link slower
+		// than the initial bitrate of the codec.
+		network := createVirtualNetwork(t,
+			LinkConfig{
+				Bandwidth: 750_000,
+				QueueSize: 30_000,
+				Delay:     20 * time.Millisecond,
+				Seed:      1,
+			},
+			LinkConfig{Delay: 20 * time.Millisecond, Seed: 2},
+		)
+		receiver, err := peer.New(append([]peer.Option{
+			peer.RegisterDefaultCodecs(),
+			peer.SetVNet(network.left, []string{"10.0.1.1"}),
+			peer.OnRemoteTrack(func(track *webrtc.TrackRemote) {
 				close(onTrack)
 				go func() {
 					buf := make([]byte, 1500)
@@ -121,42 +170,55 @@ func TestVnet(t *testing.T) {
 					}
 				}()
 			}),
-			registerPacketLogger("receiver"),
-			registerCCFB(),
-		)
+			peer.RegisterPacketLogger("receiver"),
+		}, receiverOptions...)...)
 		assert.NoError(t, err)
 
-		err = receiver.addRemoteTrack()
+		err = receiver.AddRemoteTrack()
 		assert.NoError(t, err)
 
-		sender, err := newPeer(
-			registerDefaultCodecs(),
-			onConnected(func() { close(connected) }),
-			setVNet(network.right, []string{"10.0.2.1"}),
-			registerPacketLogger("sender"),
-			registerRTPFB(),
-		)
+		var encoder *codec.Perfect
+		sender, err := peer.New(append([]peer.Option{
+			peer.RegisterDefaultCodecs(),
+			peer.OnConnected(func() { close(connected) }),
+			peer.SetVNet(network.right, []string{"10.0.2.1"}),
+			peer.RegisterPacketLogger("sender"),
+			peer.RegisterGCC(
+				gcc.OnTargetBitrateChange(func(bitrate int) {
+					slog.Info("target bitrate", "vantage-point", "sender", "ts", time.Now(), "bitrate", bitrate)
+					encoder.SetTargetBitrate(bitrate)
+				}),
+				gcc.OnRoundTripTime(func(d time.Duration) {
+					rttLock.Lock()
+					defer rttLock.Unlock()
+					rtt = d
+				}),
+				// The codec writes whole frames at once, pace them like
+				// libwebrtc does.
+				gcc.WithPacing(2.5),
+			),
+		}, senderOptions...)...)
 		assert.NoError(t, err)
 
-		track, err := sender.addLocalTrack()
+		track, err := sender.AddLocalTrack()
 		assert.NoError(t, err)
 
-		codec := newPerfectCodec(track, 1_000_000)
+		encoder = codec.NewPerfect(track, 1_000_000)
 		go func() {
 			<-connected
-			codec.start()
+			encoder.Start()
 		}()
 
-		offer, err := sender.createOffer()
+		offer, err := sender.CreateOffer()
 		assert.NoError(t, err)
 
-		err = receiver.setRemoteDescription(offer)
+		err = receiver.SetRemoteDescription(offer)
 		assert.NoError(t, err)
 
-		answer, err := receiver.createAnswer()
+		answer, err := receiver.CreateAnswer()
 		assert.NoError(t, err)
 
-		err = sender.setRemoteDescription(answer)
+		err = sender.SetRemoteDescription(answer)
 		assert.NoError(t, err)
 
 		synctest.Wait()
@@ -168,13 +230,19 @@ func TestVnet(t *testing.T) {
 		time.Sleep(10 * time.Second)
 		close(done)
 
-		err = codec.Close()
+		// The round trip time is only measured on feedback matched to the
+		// packets sent.
+		rttLock.Lock()
+		assert.GreaterOrEqual(t, rtt, 40*time.Millisecond)
+		rttLock.Unlock()
+
+		err = encoder.Close()
 		assert.NoError(t, err)
 
-		err = sender.pc.Close()
+		err = sender.Close()
 		assert.NoError(t, err)
 
-		err = receiver.pc.Close()
+		err = receiver.Close()
 		assert.NoError(t, err)
 
 		err = network.Close()